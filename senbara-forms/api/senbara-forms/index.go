@@ -3,6 +3,7 @@ package senbaraForms
 import (
 	"net/http"
 	"os"
+	"strconv"
 
 	_ "github.com/lib/pq"
 
@@ -30,19 +31,43 @@ func SenbaraFormsHandler(
 	mux.HandleFunc("GET /journal/add", c.HandleAddJournal)
 	mux.HandleFunc("GET /journal/edit", c.HandleEditJournal)
 	mux.HandleFunc("GET /journal/view", c.HandleViewJournal)
+	mux.HandleFunc("GET /journal/tags", c.HandleJournalTags)
+	mux.HandleFunc("GET /journal/stats", c.HandleJournalStats)
+	mux.HandleFunc("GET /journal/history", c.HandleJournalEntryHistory)
+	mux.HandleFunc("GET /journal/attachments", c.HandleAttachment)
+	mux.HandleFunc("GET /journal/encryption", c.HandleJournalEncryption)
+	mux.HandleFunc("GET /journal/export.zip", c.HandleExportJournal)
 
 	mux.HandleFunc("POST /journal", c.HandleCreateJournal)
 	mux.HandleFunc("POST /journal/delete", c.HandleDeleteJournal)
 	mux.HandleFunc("POST /journal/update", c.HandleUpdateJournal)
+	mux.HandleFunc("POST /journal/attachments", c.HandleCreateAttachment)
+	mux.HandleFunc("POST /journal/attachments/delete", c.HandleDeleteAttachment)
+	mux.HandleFunc("POST /journal/encryption", c.HandleEnableJournalEncryption)
+	mux.HandleFunc("POST /journal/encryption/disable", c.HandleDisableJournalEncryption)
+	mux.HandleFunc("POST /journal/unlock", c.HandleUnlockJournal)
+	mux.HandleFunc("POST /journal/lock", c.HandleLockJournal)
+	mux.HandleFunc("POST /journal/import", c.HandleImportJournal)
 
 	mux.HandleFunc("GET /contacts", c.HandleContacts)
 	mux.HandleFunc("GET /contacts/add", c.HandleAddContact)
 	mux.HandleFunc("GET /contacts/edit", c.HandleEditContact)
 	mux.HandleFunc("GET /contacts/view", c.HandleViewContact)
+	mux.HandleFunc("GET /contacts/resolve", c.HandleResolveContact)
+	mux.HandleFunc("GET /contacts/photo", c.HandleContactPhoto)
+	mux.HandleFunc("GET /contacts/duplicates", c.HandleDuplicateContacts)
+	mux.HandleFunc("GET /contacts/merge", c.HandleEditMergeContacts)
+	mux.HandleFunc("GET /contacts/fields", c.HandleCustomFields)
+	mux.HandleFunc("GET /contacts/history", c.HandleContactHistory)
 
 	mux.HandleFunc("POST /contacts", c.HandleCreateContact)
 	mux.HandleFunc("POST /contacts/delete", c.HandleDeleteContact)
 	mux.HandleFunc("POST /contacts/update", c.HandleUpdateContact)
+	mux.HandleFunc("POST /contacts/photo", c.HandleUpdateContactPhoto)
+	mux.HandleFunc("POST /contacts/photo/delete", c.HandleDeleteContactPhoto)
+	mux.HandleFunc("POST /contacts/merge", c.HandleMergeContacts)
+	mux.HandleFunc("POST /contacts/fields", c.HandleCreateCustomField)
+	mux.HandleFunc("POST /contacts/fields/delete", c.HandleDeleteCustomField)
 
 	mux.HandleFunc("GET /debts/add", c.HandleAddDebt)
 	mux.HandleFunc("GET /debts/edit", c.HandleEditDebt)
@@ -59,10 +84,30 @@ func SenbaraFormsHandler(
 	mux.HandleFunc("POST /activities/delete", c.HandleDeleteActivity)
 	mux.HandleFunc("POST /activities/update", c.HandleUpdateActivity)
 
+	mux.HandleFunc("POST /relationships", c.HandleCreateRelationship)
+	mux.HandleFunc("POST /relationships/delete", c.HandleDeleteRelationship)
+
+	mux.HandleFunc("GET /birthdays", c.HandleBirthdays)
+
+	mux.HandleFunc("GET /trash", c.HandleTrash)
+
+	mux.HandleFunc("POST /trash/restore", c.HandleRestoreTrashItem)
+	mux.HandleFunc("POST /trash/delete", c.HandleDeleteTrashItem)
+
+	mux.HandleFunc("POST /revisions/restore", c.HandleRestoreRevision)
+
+	mux.HandleFunc("GET /search", c.HandleSearch)
+
+	mux.HandleFunc("GET /settings", c.HandleSettings)
+
+	mux.HandleFunc("POST /settings", c.HandleUpdateSettings)
+
 	mux.HandleFunc("GET /userdata", c.HandleUserData)
+	mux.HandleFunc("GET /userdata/backup.zip", c.HandleBackup)
 
 	mux.HandleFunc("POST /userdata", c.HandleCreateUserData)
 	mux.HandleFunc("POST /userdata/delete", c.HandleDeleteUserData)
+	mux.HandleFunc("POST /userdata/backup", c.HandleRestoreBackup)
 
 	mux.HandleFunc("GET /login", c.HandleLogin)
 	mux.HandleFunc("GET /authorize", c.HandleAuthorize)
@@ -78,11 +123,35 @@ func Handler(w http.ResponseWriter, r *http.Request) {
 	r.URL.Path = r.URL.Query().Get("path")
 
 	if p == nil {
-		p = persisters.NewPersister(os.Getenv("POSTGRES_URL"))
+		// Only assign the persister once the schema is known to be up to date, so that a request following
+		// a failed migration or schema check runs the check again instead of serving against the wrong schema
+		persister := persisters.NewPersister(os.Getenv("POSTGRES_URL"), os.Getenv("BLOB_DIR"))
 
-		if err := p.Init(); err != nil {
+		if err := persister.Init(); err != nil {
 			panic(err)
 		}
+
+		// Cold starts of multiple instances would otherwise all try to migrate, so this can be disabled
+		// in favor of only checking the schema and running the migrate command as part of the deployment
+		autoMigrate := true
+		if v := os.Getenv("AUTO_MIGRATE"); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				panic(err)
+			}
+
+			autoMigrate = b
+		}
+
+		if autoMigrate {
+			if _, err := persister.MigrateUp(r.Context()); err != nil {
+				panic(err)
+			}
+		} else if err := persister.CheckMigrations(r.Context()); err != nil {
+			panic(err)
+		}
+
+		p = persister
 	}
 
 	if c == nil {
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/persisters"
+)
+
+// config is the configuration that is shared by all commands
+type config struct {
+	pgaddr  *string
+	blobDir *string
+}
+
+func addConfigFlags(fs *flag.FlagSet) *config {
+	return &config{
+		pgaddr:  fs.String("pgaddr", "postgresql://postgres@localhost:5432/senbara_forms?sslmode=disable", "Database address (can also be set using `POSTGRES_URL` env variable)"),
+		blobDir: fs.String("blob-dir", "", "Directory to store attachments in; if empty, attachments are stored in the database (can also be set using the BLOB_DIR env variable)"),
+	}
+}
+
+func (c *config) parseEnv() {
+	if v := os.Getenv("POSTGRES_URL"); v != "" {
+		log.Println("Using database address from POSTGRES_URL env variable")
+
+		*c.pgaddr = v
+	}
+
+	if v := os.Getenv("BLOB_DIR"); v != "" {
+		log.Println("Using blob directory from BLOB_DIR env variable")
+
+		*c.blobDir = v
+	}
+}
+
+func (c *config) openPersister() *persisters.Persister {
+	p := persisters.NewPersister(*c.pgaddr, *c.blobDir)
+
+	if err := p.Init(); err != nil {
+		panic(err)
+	}
+
+	return p
+}
+
+// openMigratedPersister opens the persister for commands that can't apply migrations
+// themselves, and exits if the database schema doesn't match this version
+func (c *config) openMigratedPersister(ctx context.Context) *persisters.Persister {
+	p := c.openPersister()
+
+	if err := p.CheckMigrations(ctx); err != nil {
+		if errors.Is(err, persisters.ErrSchemaOutdated) {
+			log.Fatalf("Could not use database: %v; run `%v %v up` to apply them", err, os.Args[0], commandMigrate)
+		}
+
+		log.Fatalln("Could not use database:", err)
+	}
+
+	return p
+}
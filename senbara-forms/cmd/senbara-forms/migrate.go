@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	migrateUp     = "up"
+	migrateDown   = "down"
+	migrateStatus = "status"
+)
+
+var errUnknownMigrateCommand = errors.New("unknown migrate command, expected up, down or status")
+
+func migrate(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet(commandMigrate, flag.ExitOnError)
+
+	cfg := addConfigFlags(fs)
+
+	if err := fs.Parse(args); err != nil {
+		panic(err)
+	}
+
+	cfg.parseEnv()
+
+	if fs.NArg() != 1 {
+		panic(errUnknownMigrateCommand)
+	}
+
+	command := fs.Arg(0)
+	if command != migrateUp && command != migrateDown && command != migrateStatus {
+		panic(errUnknownMigrateCommand)
+	}
+
+	p := cfg.openPersister()
+
+	switch command {
+	case migrateUp:
+		migrationResults, err := p.MigrateUp(ctx)
+		if err != nil {
+			panic(err)
+		}
+
+		if len(migrationResults) == 0 {
+			fmt.Println("No pending migrations")
+		}
+
+		for _, migrationResult := range migrationResults {
+			fmt.Println(migrationResult)
+		}
+
+	case migrateDown:
+		migrationResult, err := p.MigrateDown(ctx)
+		if err != nil {
+			panic(err)
+		}
+
+		fmt.Println(migrationResult)
+
+	case migrateStatus:
+		migrationStatuses, err := p.GetMigrationStatus(ctx)
+		if err != nil {
+			panic(err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+		fmt.Fprintln(w, "VERSION\tSTATE\tAPPLIED AT\tSOURCE")
+		for _, migrationStatus := range migrationStatuses {
+			appliedAt := "-"
+			if !migrationStatus.AppliedAt.IsZero() {
+				appliedAt = migrationStatus.AppliedAt.Format(time.RFC3339)
+			}
+
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", migrationStatus.Source.Version, migrationStatus.State, appliedAt, migrationStatus.Source.Path)
+		}
+
+		if err := w.Flush(); err != nil {
+			panic(err)
+		}
+	}
+}
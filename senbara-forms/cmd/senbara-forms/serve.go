@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	senbaraForms "github.com/pojntfx/senbara/senbara-forms/api/senbara-forms"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/backups"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/controllers"
+)
+
+var (
+	errMissingOIDCIssuer      = errors.New("missing OIDC issuer")
+	errMissingOIDCClientID    = errors.New("missing OIDC client ID")
+	errMissingOIDCRedirectURL = errors.New("missing OIDC redirect URL")
+	errMissingPrivacyURL      = errors.New("missing privacy policy URL")
+	errMissingImprintURL      = errors.New("missing imprint URL")
+)
+
+func serve(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet(commandServe, flag.ExitOnError)
+
+	cfg := addConfigFlags(fs)
+
+	laddr := fs.String("laddr", ":1337", "Listen address (port can also be set with `PORT` env variable)")
+	autoMigrate := fs.Bool("auto-migrate", true, "Whether to apply pending migrations on startup; if false, the server refuses to start unless they were applied with the migrate command (can also be set using the AUTO_MIGRATE env variable)")
+	oidcIssuer := fs.String("oidc-issuer", "", "OIDC Issuer (i.e. https://pojntfx.eu.auth0.com/) (can also be set using the OIDC_ISSUER env variable)")
+	oidcClientID := fs.String("oidc-client-id", "", "OIDC Client ID (i.e. myoidcclientid) (can also be set using the OIDC_CLIENT_ID env variable)")
+	oidcRedirectURL := fs.String("oidc-redirect-url", "http://localhost:1337/authorize", "OIDC redirect URL (can also be set using the OIDC_REDIRECT_URL env variable)")
+	trashPurgeInterval := fs.Duration("trash-purge-interval", time.Hour, "Interval in which expired trash items are removed for good; if zero or negative, expired trash items are kept (can also be set using the TRASH_PURGE_INTERVAL env variable)")
+	backupDir := fs.String("backup-dir", "", "Directory to write automatic backups of all users to; if empty, automatic backups are disabled (can also be set using the BACKUP_DIR env variable)")
+	backupInterval := fs.Duration("backup-interval", 24*time.Hour, "Interval in which automatic backups are written; if zero or negative, automatic backups are disabled (can also be set using the BACKUP_INTERVAL env variable)")
+	backupKeepDaily := fs.Int("backup-keep-daily", 7, "Number of daily automatic backups to keep per user (can also be set using the BACKUP_KEEP_DAILY env variable)")
+	backupKeepWeekly := fs.Int("backup-keep-weekly", 4, "Number of weekly automatic backups to keep per user (can also be set using the BACKUP_KEEP_WEEKLY env variable)")
+	backupKeepMonthly := fs.Int("backup-keep-monthly", 12, "Number of monthly automatic backups to keep per user (can also be set using the BACKUP_KEEP_MONTHLY env variable)")
+	privacyURL := fs.String("privacy-url", "", "Privacy policy URL (can also be set using the PRIVACY_URL env variable)")
+	imprintURL := fs.String("imprint-url", "", "Imprint URL (can also be set using the IMPRINT_URL env variable)")
+
+	if err := fs.Parse(args); err != nil {
+		panic(err)
+	}
+
+	cfg.parseEnv()
+
+	if v := os.Getenv("PORT"); v != "" {
+		log.Println("Using port from PORT env variable")
+
+		la, err := net.ResolveTCPAddr("tcp", *laddr)
+		if err != nil {
+			panic(err)
+		}
+
+		p, err := strconv.Atoi(v)
+		if err != nil {
+			panic(err)
+		}
+
+		la.Port = p
+		*laddr = la.String()
+	}
+
+	if v := os.Getenv("AUTO_MIGRATE"); v != "" {
+		log.Println("Using auto-migrate setting from AUTO_MIGRATE env variable")
+
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			panic(err)
+		}
+
+		*autoMigrate = b
+	}
+
+	if v := os.Getenv("TRASH_PURGE_INTERVAL"); v != "" {
+		log.Println("Using trash purge interval from TRASH_PURGE_INTERVAL env variable")
+
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			panic(err)
+		}
+
+		*trashPurgeInterval = d
+	}
+
+	if v := os.Getenv("BACKUP_DIR"); v != "" {
+		log.Println("Using backup directory from BACKUP_DIR env variable")
+
+		*backupDir = v
+	}
+
+	if v := os.Getenv("BACKUP_INTERVAL"); v != "" {
+		log.Println("Using backup interval from BACKUP_INTERVAL env variable")
+
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			panic(err)
+		}
+
+		*backupInterval = d
+	}
+
+	if v := os.Getenv("BACKUP_KEEP_DAILY"); v != "" {
+		log.Println("Using number of daily backups to keep from BACKUP_KEEP_DAILY env variable")
+
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			panic(err)
+		}
+
+		*backupKeepDaily = n
+	}
+
+	if v := os.Getenv("BACKUP_KEEP_WEEKLY"); v != "" {
+		log.Println("Using number of weekly backups to keep from BACKUP_KEEP_WEEKLY env variable")
+
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			panic(err)
+		}
+
+		*backupKeepWeekly = n
+	}
+
+	if v := os.Getenv("BACKUP_KEEP_MONTHLY"); v != "" {
+		log.Println("Using number of monthly backups to keep from BACKUP_KEEP_MONTHLY env variable")
+
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			panic(err)
+		}
+
+		*backupKeepMonthly = n
+	}
+
+	if v := os.Getenv("OIDC_ISSUER"); v != "" {
+		log.Println("Using OIDC issuer from OIDC_ISSUER env variable")
+
+		*oidcIssuer = v
+	}
+
+	if v := os.Getenv("OIDC_CLIENT_ID"); v != "" {
+		log.Println("Using OIDC client ID from OIDC_CLIENT_ID env variable")
+
+		*oidcClientID = v
+	}
+
+	if v := os.Getenv("OIDC_REDIRECT_URL"); v != "" {
+		log.Println("Using OIDC redirect URL from OIDC_REDIRECT_URL env variable")
+
+		*oidcRedirectURL = v
+	}
+
+	if v := os.Getenv("PRIVACY_URL"); v != "" {
+		log.Println("Using privacy policy URL from PRIVACY_URL env variable")
+
+		*privacyURL = v
+	}
+
+	if v := os.Getenv("IMPRINT_URL"); v != "" {
+		log.Println("Using imprint URL from IMPRINT_URL env variable")
+
+		*imprintURL = v
+	}
+
+	if strings.TrimSpace(*oidcIssuer) == "" {
+		panic(errMissingOIDCIssuer)
+	}
+
+	if strings.TrimSpace(*oidcClientID) == "" {
+		panic(errMissingOIDCClientID)
+	}
+
+	if strings.TrimSpace(*oidcRedirectURL) == "" {
+		panic(errMissingOIDCRedirectURL)
+	}
+
+	if strings.TrimSpace(*privacyURL) == "" {
+		panic(errMissingPrivacyURL)
+	}
+
+	if strings.TrimSpace(*imprintURL) == "" {
+		panic(errMissingImprintURL)
+	}
+
+	p := cfg.openPersister()
+
+	if *autoMigrate {
+		migrationResults, err := p.MigrateUp(ctx)
+		if err != nil {
+			panic(err)
+		}
+
+		for _, migrationResult := range migrationResults {
+			log.Println("Applied migration", migrationResult)
+		}
+	} else if err := p.CheckMigrations(ctx); err != nil {
+		panic(err)
+	}
+
+	if *trashPurgeInterval > 0 {
+		go func() {
+			t := time.NewTicker(*trashPurgeInterval)
+			defer t.Stop()
+
+			for {
+				purged, err := p.PurgeTrash(ctx)
+				if err != nil {
+					log.Println("Could not purge trash:", err)
+				} else if purged > 0 {
+					log.Println("Purged", purged, "expired trash items")
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+
+				case <-t.C:
+				}
+			}
+		}()
+	}
+
+	if strings.TrimSpace(*backupDir) != "" && *backupInterval > 0 {
+		s := backups.NewScheduler(p, *backupDir, *backupInterval, backups.Retention{
+			Daily:   *backupKeepDaily,
+			Weekly:  *backupKeepWeekly,
+			Monthly: *backupKeepMonthly,
+		})
+
+		go func() {
+			t := time.NewTicker(*backupInterval)
+			defer t.Stop()
+
+			for {
+				backedUp, err := s.Run(ctx)
+				if err != nil {
+					log.Println("Could not back up all users:", err)
+				}
+
+				if backedUp > 0 {
+					log.Println("Backed up", backedUp, "users to", *backupDir)
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+
+				case <-t.C:
+				}
+			}
+		}()
+	}
+
+	c := controllers.NewController(
+		p,
+
+		*oidcIssuer,
+		*oidcClientID,
+		*oidcRedirectURL,
+
+		*privacyURL,
+		*imprintURL,
+	)
+
+	if err := c.Init(ctx); err != nil {
+		panic(err)
+	}
+
+	log.Println("Listening on", *laddr)
+
+	panic(http.ListenAndServe(*laddr, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		senbaraForms.SenbaraFormsHandler(w, r, c)
+	})))
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/persisters"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/userdata"
+)
+
+const (
+	formatZip   = "zip"
+	formatJSONL = "jsonl"
+)
+
+var (
+	errMissingNamespace = errors.New("missing namespace")
+	errMissingInput     = errors.New("missing input")
+	errUnknownFormat    = errors.New("unknown format, expected zip or jsonl")
+)
+
+// userDataFlags are the flags that are shared by the commands that read or write the user data of a namespace
+type userDataFlags struct {
+	*config
+
+	namespace         *string
+	format            *string
+	journalPassphrase *string
+}
+
+func addUserDataFlags(fs *flag.FlagSet) *userDataFlags {
+	return &userDataFlags{
+		config: addConfigFlags(fs),
+
+		namespace:         fs.String("namespace", "", "Namespace (the email address of the user) to use"),
+		format:            fs.String("format", formatZip, "Format to use (zip for backup archives, jsonl for JSON lines)"),
+		journalPassphrase: fs.String("journal-passphrase", "", "Passphrase of the journal if it is encrypted (can also be set using the JOURNAL_PASSPHRASE env variable)"),
+	}
+}
+
+func (f *userDataFlags) parseEnv() {
+	f.config.parseEnv()
+
+	if v := os.Getenv("JOURNAL_PASSPHRASE"); v != "" {
+		log.Println("Using journal passphrase from JOURNAL_PASSPHRASE env variable")
+
+		*f.journalPassphrase = v
+	}
+
+	if strings.TrimSpace(*f.namespace) == "" {
+		panic(errMissingNamespace)
+	}
+
+	if *f.format != formatZip && *f.format != formatJSONL {
+		panic(errUnknownFormat)
+	}
+}
+
+// getJournalKey unlocks the journal of the namespace if a passphrase is set. Without one, the data of
+// encrypted journals can't be read or written.
+func (f *userDataFlags) getJournalKey(ctx context.Context, p *persisters.Persister) []byte {
+	if *f.journalPassphrase == "" {
+		return nil
+	}
+
+	key, err := p.UnlockJournal(ctx, *f.journalPassphrase, *f.namespace)
+	if err != nil {
+		panic(err)
+	}
+
+	return key
+}
+
+func exportUserData(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet(commandExport, flag.ExitOnError)
+
+	flags := addUserDataFlags(fs)
+	output := fs.String("output", "-", "File to write the user data to (- for stdout)")
+
+	if err := fs.Parse(args); err != nil {
+		panic(err)
+	}
+
+	flags.parseEnv()
+
+	p := flags.openMigratedPersister(ctx)
+	key := flags.getJournalKey(ctx, p)
+
+	w := os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			panic(err)
+		}
+		defer f.Close()
+
+		w = f
+	}
+
+	if *flags.format == formatZip {
+		if err := userdata.ExportArchive(ctx, p, key, *flags.namespace, w); err != nil {
+			panic(err)
+		}
+	} else {
+		if err := userdata.Export(ctx, p, key, *flags.namespace, w); err != nil {
+			panic(err)
+		}
+	}
+
+	// Errors while flushing the file to disk would otherwise go unnoticed
+	if err := w.Sync(); err != nil && w != os.Stdout {
+		panic(err)
+	}
+
+	log.Println("Exported user data of", *flags.namespace)
+}
+
+func importUserData(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet(commandImport, flag.ExitOnError)
+
+	flags := addUserDataFlags(fs)
+	input := fs.String("input", "", "File to read the user data from (- for stdin, which isn't supported for zip)")
+
+	if err := fs.Parse(args); err != nil {
+		panic(err)
+	}
+
+	flags.parseEnv()
+
+	if strings.TrimSpace(*input) == "" {
+		panic(errMissingInput)
+	}
+
+	p := flags.openMigratedPersister(ctx)
+	key := flags.getJournalKey(ctx, p)
+
+	if *flags.format == formatJSONL {
+		var r io.Reader = os.Stdin
+		if *input != "-" {
+			f, err := os.Open(*input)
+			if err != nil {
+				panic(err)
+			}
+			defer f.Close()
+
+			r = f
+		}
+
+		if err := userdata.Import(ctx, p, key, *flags.namespace, r); err != nil {
+			panic(err)
+		}
+	} else {
+		// Backup archives need random access to their files
+		f, err := os.Open(*input)
+		if err != nil {
+			panic(err)
+		}
+		defer f.Close()
+
+		info, err := f.Stat()
+		if err != nil {
+			panic(err)
+		}
+
+		if err := userdata.ImportArchive(ctx, p, key, *flags.namespace, f, info.Size()); err != nil {
+			panic(err)
+		}
+	}
+
+	log.Println("Imported user data into", *flags.namespace)
+}
+
+func deleteNamespace(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet(commandDeleteNamespace, flag.ExitOnError)
+
+	cfg := addConfigFlags(fs)
+	namespace := fs.String("namespace", "", "Namespace (the email address of the user) to delete the user data of")
+
+	if err := fs.Parse(args); err != nil {
+		panic(err)
+	}
+
+	cfg.parseEnv()
+
+	if strings.TrimSpace(*namespace) == "" {
+		panic(errMissingNamespace)
+	}
+
+	p := cfg.openMigratedPersister(ctx)
+
+	if err := p.DeleteUserData(ctx, *namespace); err != nil {
+		panic(err)
+	}
+
+	log.Println("Deleted user data of", *namespace)
+}
@@ -0,0 +1,243 @@
+package backups
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/persisters"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/userdata"
+)
+
+const (
+	fileNamePrefix     = "senbara-forms-backup-"
+	fileNameSuffix     = ".zip"
+	fileNameTimeFormat = "20060102T150405Z"
+)
+
+// Retention is the number of daily, weekly and monthly backups that are kept for each namespace.
+// The newest backup of each day, week and month counts towards them; the newest backup is always kept.
+type Retention struct {
+	Daily   int
+	Weekly  int
+	Monthly int
+}
+
+// Scheduler writes the backup archives of all namespaces to a local directory, with one directory per namespace
+type Scheduler struct {
+	persister *persisters.Persister
+
+	dir       string
+	interval  time.Duration
+	retention Retention
+}
+
+func NewScheduler(
+	persister *persisters.Persister,
+
+	dir string,
+	interval time.Duration,
+	retention Retention,
+) *Scheduler {
+	return &Scheduler{
+		persister: persister,
+
+		dir:       dir,
+		interval:  interval,
+		retention: retention,
+	}
+}
+
+// Run backs up all namespaces that weren't backed up within the interval and returns how many were
+// backed up. Failed backups of individual namespaces are recorded in their backup runs.
+func (s *Scheduler) Run(ctx context.Context) (int, error) {
+	namespaces, err := s.persister.GetNamespaces(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		backedUp = 0
+		errs     = []error{}
+	)
+	for _, namespace := range namespaces {
+		now := time.Now().UTC()
+
+		backupRun, err := s.persister.GetBackupRun(ctx, namespace)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		// Only half of the interval has to have passed since the backups of the previous run
+		// took some time, but restarts of the server shouldn't lead to additional backups
+		if err == nil && now.Sub(backupRun.RanAt) < s.interval/2 {
+			continue
+		}
+
+		fileName, size, journalSkipped, backupErr := s.backup(ctx, namespace, now)
+
+		if err := s.persister.UpdateBackupRun(ctx, now, fileName, size, journalSkipped, backupErr, namespace); err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		if backupErr != nil {
+			errs = append(errs, fmt.Errorf("could not back up namespace %v: %w", namespace, backupErr))
+
+			continue
+		}
+
+		backedUp++
+	}
+
+	return backedUp, errors.Join(errs...)
+}
+
+func (s *Scheduler) backup(ctx context.Context, namespace string, now time.Time) (string, int64, bool, error) {
+	// Namespaces are email addresses, which can contain characters that aren't allowed in paths
+	dir := filepath.Join(s.dir, url.PathEscape(namespace))
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", 0, false, err
+	}
+
+	fileName := fileNamePrefix + now.Format(fileNameTimeFormat) + fileNameSuffix
+
+	// Backups are written to a temporary file first so that incomplete backups are never pruned in favor of them
+	f, err := os.CreateTemp(dir, "."+fileName+"-*")
+	if err != nil {
+		return "", 0, false, err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	// The key of encrypted journals is only known while they are unlocked, so they are left out
+	// instead of failing the backup of everything else
+	journalSkipped, err := userdata.ExportArchiveWithoutLockedJournal(ctx, s.persister, nil, namespace, f)
+	if err != nil {
+		return "", 0, false, err
+	}
+
+	if err := f.Sync(); err != nil {
+		return "", 0, false, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", 0, false, err
+	}
+
+	if err := f.Close(); err != nil {
+		return "", 0, false, err
+	}
+
+	if err := os.Rename(f.Name(), filepath.Join(dir, fileName)); err != nil {
+		return "", 0, false, err
+	}
+
+	if err := s.prune(dir); err != nil {
+		return "", 0, false, err
+	}
+
+	return fileName, info.Size(), journalSkipped, nil
+}
+
+// prune removes the backups in `dir` that aren't kept by the retention
+func (s *Scheduler) prune(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	type backupFile struct {
+		name      string
+		createdAt time.Time
+	}
+
+	backupFiles := []backupFile{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, fileNamePrefix) || !strings.HasSuffix(name, fileNameSuffix) {
+			continue
+		}
+
+		createdAt, err := time.Parse(fileNameTimeFormat, strings.TrimSuffix(strings.TrimPrefix(name, fileNamePrefix), fileNameSuffix))
+		if err != nil {
+			// Files that weren't created by the scheduler are left alone
+			continue
+		}
+
+		backupFiles = append(backupFiles, backupFile{name, createdAt})
+	}
+
+	slices.SortFunc(backupFiles, func(a, b backupFile) int {
+		return b.createdAt.Compare(a.createdAt)
+	})
+
+	createdAts := []time.Time{}
+	for _, backupFile := range backupFiles {
+		createdAts = append(createdAts, backupFile.createdAt)
+	}
+
+	kept := getKeptBackups(createdAts, s.retention)
+	for i, backupFile := range backupFiles {
+		if _, ok := kept[i]; ok {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(dir, backupFile.name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getKeptBackups returns the indexes of the backups to keep, given their creation times sorted newest first
+func getKeptBackups(createdAts []time.Time, retention Retention) map[int]struct{} {
+	kept := map[int]struct{}{}
+	if len(createdAts) == 0 {
+		return kept
+	}
+
+	kept[0] = struct{}{}
+
+	for _, period := range []struct {
+		count int
+		key   func(t time.Time) string
+	}{
+		{retention.Daily, func(t time.Time) string { return t.Format("2006-01-02") }},
+		{retention.Weekly, func(t time.Time) string {
+			year, week := t.ISOWeek()
+
+			return fmt.Sprintf("%v-%v", year, week)
+		}},
+		{retention.Monthly, func(t time.Time) string { return t.Format("2006-01") }},
+	} {
+		periods := map[string]struct{}{}
+		for i, createdAt := range createdAts {
+			if len(periods) >= period.count {
+				break
+			}
+
+			key := period.key(createdAt)
+			if _, ok := periods[key]; ok {
+				continue
+			}
+
+			periods[key] = struct{}{}
+			kept[i] = struct{}{}
+		}
+	}
+
+	return kept
+}
@@ -0,0 +1,166 @@
+package backups
+
+import (
+	"maps"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGetKeptBackups(t *testing.T) {
+	tests := []struct {
+		name       string
+		createdAts []string
+		retention  Retention
+		kept       []int
+	}{
+		{
+			name:       "no backups",
+			createdAts: []string{},
+			retention:  Retention{Daily: 7, Weekly: 4, Monthly: 12},
+			kept:       []int{},
+		},
+		{
+			name: "newest backup is kept if all counts are zero",
+			createdAts: []string{
+				"2026-10-18T12:00:00Z",
+				"2026-10-17T12:00:00Z",
+				"2026-09-17T12:00:00Z",
+			},
+			retention: Retention{},
+			kept:      []int{0},
+		},
+		{
+			name: "newest backup is kept if counts are negative",
+			createdAts: []string{
+				"2026-10-18T12:00:00Z",
+				"2026-10-17T12:00:00Z",
+			},
+			retention: Retention{Daily: -1, Weekly: -1, Monthly: -1},
+			kept:      []int{0},
+		},
+		{
+			name: "only the newest backup of each day counts",
+			createdAts: []string{
+				"2026-10-18T23:00:00Z",
+				"2026-10-18T12:00:00Z",
+				"2026-10-18T00:00:00Z",
+				"2026-10-17T23:59:59Z",
+				"2026-10-17T00:00:00Z",
+				"2026-10-16T12:00:00Z",
+			},
+			retention: Retention{Daily: 2},
+			kept:      []int{0, 3},
+		},
+		{
+			name: "daily count larger than the number of days keeps one backup per day",
+			createdAts: []string{
+				"2026-10-18T12:00:00Z",
+				"2026-10-18T06:00:00Z",
+				"2026-10-17T12:00:00Z",
+			},
+			retention: Retention{Daily: 7},
+			kept:      []int{0, 2},
+		},
+		{
+			name: "weeks start on Monday",
+			createdAts: []string{
+				"2026-10-19T12:00:00Z", // Monday, week 43
+				"2026-10-18T12:00:00Z", // Sunday, week 42
+				"2026-10-12T12:00:00Z", // Monday, week 42
+				"2026-10-11T12:00:00Z", // Sunday, week 41
+			},
+			retention: Retention{Weekly: 3},
+			kept:      []int{0, 1, 3},
+		},
+		{
+			name: "ISO week 53 spans the turn of the year",
+			createdAts: []string{
+				"2021-01-04T12:00:00Z", // 2021-W01
+				"2021-01-03T12:00:00Z", // 2020-W53
+				"2020-12-31T12:00:00Z", // 2020-W53
+				"2020-12-28T12:00:00Z", // 2020-W53
+				"2020-12-27T12:00:00Z", // 2020-W52
+			},
+			retention: Retention{Weekly: 3},
+			kept:      []int{0, 1, 4},
+		},
+		{
+			name: "ISO week 1 starts in the previous year",
+			createdAts: []string{
+				"2026-01-02T12:00:00Z", // 2026-W01
+				"2025-12-29T12:00:00Z", // 2026-W01
+				"2025-12-28T12:00:00Z", // 2025-W52
+			},
+			retention: Retention{Weekly: 2},
+			kept:      []int{0, 2},
+		},
+		{
+			name: "same week number in different years is a different week",
+			createdAts: []string{
+				"2026-10-18T12:00:00Z", // 2026-W42
+				"2025-10-18T12:00:00Z", // 2025-W42
+			},
+			retention: Retention{Weekly: 2},
+			kept:      []int{0, 1},
+		},
+		{
+			name: "months are counted across the turn of the year",
+			createdAts: []string{
+				"2026-01-31T12:00:00Z",
+				"2026-01-01T00:00:00Z",
+				"2025-12-31T23:59:59Z",
+				"2025-12-01T00:00:00Z",
+				"2025-11-30T12:00:00Z",
+			},
+			retention: Retention{Monthly: 2},
+			kept:      []int{0, 2},
+		},
+		{
+			name: "periods overlap",
+			createdAts: []string{
+				"2026-10-18T12:00:00Z", // Sunday
+				"2026-10-17T12:00:00Z",
+				"2026-10-16T12:00:00Z",
+				"2026-10-11T12:00:00Z", // Sunday of the previous week
+				"2026-09-30T12:00:00Z",
+				"2026-08-31T12:00:00Z",
+			},
+			retention: Retention{Daily: 2, Weekly: 2, Monthly: 3},
+			kept:      []int{0, 1, 3, 4, 5},
+		},
+		{
+			name: "only the newest backup is kept if the others are in the same periods",
+			createdAts: []string{
+				"2026-10-18T12:00:00Z",
+				"2026-10-18T06:00:00Z",
+				"2026-10-18T00:00:00Z",
+			},
+			retention: Retention{Daily: 7, Weekly: 4, Monthly: 12},
+			kept:      []int{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			createdAts := []time.Time{}
+			for _, createdAt := range tt.createdAts {
+				c, err := time.Parse(time.RFC3339, createdAt)
+				if err != nil {
+					t.Fatalf("could not parse creation time: %v", err)
+				}
+
+				createdAts = append(createdAts, c)
+			}
+
+			kept := slices.Sorted(maps.Keys(getKeptBackups(createdAts, tt.retention)))
+			if kept == nil {
+				kept = []int{}
+			}
+
+			if !slices.Equal(kept, tt.kept) {
+				t.Errorf("getKeptBackups() kept %v, want %v", kept, tt.kept)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package blobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	directoryKeyLength = 16
+)
+
+// DirectoryStore stores each blob as a file in a local directory, using random file names as keys
+type DirectoryStore struct {
+	dir string
+}
+
+func NewDirectoryStore(dir string) *DirectoryStore {
+	return &DirectoryStore{
+		dir: dir,
+	}
+}
+
+func (s *DirectoryStore) Init() error {
+	return os.MkdirAll(s.dir, 0700)
+}
+
+func (s *DirectoryStore) getPath(key string) (string, error) {
+	if b, err := hex.DecodeString(key); err != nil || len(b) != directoryKeyLength {
+		return "", ErrInvalidKey
+	}
+
+	return filepath.Join(s.dir, key), nil
+}
+
+func (s *DirectoryStore) Put(ctx context.Context, data []byte) (string, error) {
+	b := make([]byte, directoryKeyLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	key := hex.EncodeToString(b)
+
+	// Write to a temporary file first so that a blob is never visible half-written
+	f, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+
+		return "", err
+	}
+
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(f.Name(), filepath.Join(s.dir, key)); err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+func (s *DirectoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	p, err := s.getPath(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(p)
+}
+
+func (s *DirectoryStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.getPath(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(p)
+}
+
+func (s *DirectoryStore) Delete(ctx context.Context, key string) error {
+	p, err := s.getPath(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
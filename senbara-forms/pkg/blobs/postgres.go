@@ -0,0 +1,120 @@
+package blobs
+
+import (
+	"context"
+	"io"
+	"strconv"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/tables"
+)
+
+const (
+	largeObjectChunkSize = 1024 * 1024
+)
+
+// PostgresStore stores blobs as Postgres large objects, using their OIDs as keys
+type PostgresStore struct {
+	queries *tables.Queries
+}
+
+func NewPostgresStore(queries *tables.Queries) *PostgresStore {
+	return &PostgresStore{
+		queries: queries,
+	}
+}
+
+func (s *PostgresStore) Put(ctx context.Context, data []byte) (string, error) {
+	oid, err := s.queries.CreateLargeObject(ctx, data)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.FormatInt(oid, 10), nil
+}
+
+func (s *PostgresStore) Get(ctx context.Context, key string) ([]byte, error) {
+	oid, err := strconv.ParseInt(key, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+
+	return s.queries.GetLargeObject(ctx, oid)
+}
+
+func (s *PostgresStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	oid, err := strconv.ParseInt(key, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+
+	r := &largeObjectReader{
+		ctx:     ctx,
+		queries: s.queries,
+		oid:     oid,
+	}
+
+	// Reading the first chunk right away fails early if the large object doesn't exist
+	if err := r.readChunk(); err != nil {
+		return nil, err
+	}
+
+	return io.NopCloser(r), nil
+}
+
+func (s *PostgresStore) Delete(ctx context.Context, key string) error {
+	oid, err := strconv.ParseInt(key, 10, 64)
+	if err != nil {
+		return ErrInvalidKey
+	}
+
+	return s.queries.DeleteLargeObject(ctx, oid)
+}
+
+// largeObjectReader reads a large object one chunk at a time
+type largeObjectReader struct {
+	ctx     context.Context
+	queries *tables.Queries
+	oid     int64
+
+	offset int64
+	chunk  []byte
+	done   bool
+}
+
+func (r *largeObjectReader) readChunk() error {
+	chunk, err := r.queries.GetLargeObjectChunk(r.ctx, tables.GetLargeObjectChunkParams{
+		Oid:         r.oid,
+		ChunkOffset: r.offset,
+		ChunkLength: largeObjectChunkSize,
+	})
+	if err != nil {
+		return err
+	}
+
+	r.offset += int64(len(chunk))
+	r.chunk = chunk
+	r.done = len(chunk) < largeObjectChunkSize
+
+	return nil
+}
+
+func (r *largeObjectReader) Read(p []byte) (int, error) {
+	if len(r.chunk) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+
+		if err := r.readChunk(); err != nil {
+			return 0, err
+		}
+
+		if len(r.chunk) == 0 {
+			return 0, io.EOF
+		}
+	}
+
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+
+	return n, nil
+}
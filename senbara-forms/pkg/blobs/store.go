@@ -0,0 +1,21 @@
+package blobs
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// Store keeps the contents of attachments outside of the regular tables. Blobs are
+// immutable; `Put` returns the key they can later be fetched and deleted with. `Open`
+// streams a blob instead of reading it into memory at once like `Get` does.
+type Store interface {
+	Put(ctx context.Context, data []byte) (string, error)
+	Get(ctx context.Context, key string) ([]byte, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
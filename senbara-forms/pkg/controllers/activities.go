@@ -1,6 +1,8 @@
 package controllers
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -9,11 +11,13 @@ import (
 	"time"
 
 	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/persisters"
 )
 
 type activityData struct {
 	pageData
-	Entry models.GetActivityAndContactRow
+	Entry    models.GetActivityAndContactRow
+	Conflict bool
 }
 
 func (b *Controller) HandleAddActivity(w http.ResponseWriter, r *http.Request) {
@@ -315,10 +319,20 @@ func (b *Controller) HandleUpdateActivity(w http.ResponseWriter, r *http.Request
 
 	description := r.FormValue("description")
 
+	version, err := strconv.Atoi(r.FormValue("version"))
+	if err != nil {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
 	if err := b.persister.UpdateActivity(
 		r.Context(),
 
 		int32(id),
+		int32(version),
 
 		int32(contactID),
 		userData.Email,
@@ -327,6 +341,20 @@ func (b *Controller) HandleUpdateActivity(w http.ResponseWriter, r *http.Request
 		date,
 		description,
 	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		if errors.Is(err, persisters.ErrVersionConflict) {
+			log.Println(errCouldNotUpdateInDB, err)
+
+			b.renderEditActivity(w, r, userData, int32(id), int32(contactID), true)
+
+			return
+		}
+
 		log.Println(errCouldNotUpdateInDB, err)
 
 		http.Error(w, errCouldNotUpdateInDB.Error(), http.StatusInternalServerError)
@@ -385,7 +413,13 @@ func (b *Controller) HandleEditActivity(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	activityAndContact, err := b.persister.GetActivityAndContact(r.Context(), int32(id), int32(contactID), userData.Email)
+	b.renderEditActivity(w, r, userData, int32(id), int32(contactID), false)
+}
+
+// renderEditActivity renders the edit page with the current values of an activity. If `conflict` is
+// set, the page explains that the activity was changed while it was being edited.
+func (b *Controller) renderEditActivity(w http.ResponseWriter, r *http.Request, userData userData, id, contactID int32, conflict bool) {
+	activityAndContact, err := b.persister.GetActivityAndContact(r.Context(), id, contactID, userData.Email)
 	if err != nil {
 		log.Println(errCouldNotFetchFromDB, err)
 
@@ -394,6 +428,10 @@ func (b *Controller) HandleEditActivity(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if conflict {
+		w.WriteHeader(http.StatusConflict)
+	}
+
 	if err := b.tpl.ExecuteTemplate(w, "activities_edit.html", activityData{
 		pageData: pageData{
 			userData: userData,
@@ -402,7 +440,8 @@ func (b *Controller) HandleEditActivity(w http.ResponseWriter, r *http.Request)
 			PrivacyURL: b.privacyURL,
 			ImprintURL: b.imprintURL,
 		},
-		Entry: activityAndContact,
+		Entry:    activityAndContact,
+		Conflict: conflict,
 	}); err != nil {
 		log.Println(errCouldNotRenderTemplate, err)
 
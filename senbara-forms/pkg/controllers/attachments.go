@@ -0,0 +1,292 @@
+package controllers
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/persisters"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+const (
+	maxAttachmentUploadSize = 25 * 1024 * 1024
+
+	attachmentURLScheme = "attachment:"
+)
+
+var (
+	// inlineAttachmentContentTypes can be shown in the browser, all other attachments are downloaded
+	inlineAttachmentContentTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+)
+
+// attachmentURLTransformer points links and images with `attachment:ID` destinations to the route that serves the attachment
+type attachmentURLTransformer struct{}
+
+func (t *attachmentURLTransformer) Transform(node *ast.Document, reader text.Reader, pc parser.Context) {
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := n.(type) {
+		case *ast.Image:
+			n.Destination = getAttachmentURL(n.Destination)
+
+		case *ast.Link:
+			n.Destination = getAttachmentURL(n.Destination)
+		}
+
+		return ast.WalkContinue, nil
+	})
+}
+
+// isInlineAttachment reports whether an attachment can be shown in the browser, e.g. as an image in the body of a journal entry
+func isInlineAttachment(contentType string) bool {
+	return slices.Contains(inlineAttachmentContentTypes, contentType)
+}
+
+func getAttachmentURL(destination []byte) []byte {
+	rid, ok := bytes.CutPrefix(destination, []byte(attachmentURLScheme))
+	if !ok {
+		return destination
+	}
+
+	if _, err := strconv.Atoi(string(rid)); err != nil {
+		return destination
+	}
+
+	return []byte("/journal/attachments?id=" + string(rid))
+}
+
+func (b *Controller) HandleAttachment(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	rid := r.URL.Query().Get("id")
+	if strings.TrimSpace(rid) == "" {
+		log.Println(errInvalidQueryParam)
+
+		http.Error(w, errInvalidQueryParam.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	id, err := strconv.Atoi(rid)
+	if err != nil {
+		log.Println(errInvalidQueryParam)
+
+		http.Error(w, errInvalidQueryParam.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	attachment, data, err := b.persister.GetAttachment(r.Context(), int32(id), userData.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	disposition := "attachment"
+	if isInlineAttachment(attachment.ContentType) {
+		disposition = "inline"
+	}
+
+	// Uploaded files are untrusted, so browsers may neither guess another content type nor run scripts in them.
+	// Like contact photos, attachments are private to the namespace and are revalidated with the ETag.
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Name}))
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; sandbox")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Set("ETag", fmt.Sprintf(`"%v"`, attachment.ID))
+
+	http.ServeContent(w, r, "", attachment.CreatedAt, bytes.NewReader(data))
+}
+
+func (b *Controller) HandleCreateAttachment(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxAttachmentUploadSize)
+
+	if err := r.ParseMultipartForm(maxAttachmentUploadSize); err != nil {
+		log.Println(errCouldNotParseForm, err)
+
+		http.Error(w, errCouldNotParseForm.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	rjournalEntryID := r.FormValue("journal_entry_id")
+	if strings.TrimSpace(rjournalEntryID) == "" {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	journalEntryID, err := strconv.Atoi(rjournalEntryID)
+	if err != nil {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	file, header, err := r.FormFile("attachment")
+	if err != nil {
+		log.Println(errCouldNotReadRequest, err)
+
+		http.Error(w, errCouldNotReadRequest.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		log.Println(errCouldNotReadRequest, err)
+
+		http.Error(w, errCouldNotReadRequest.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	name := strings.TrimSpace(filepath.Base(header.Filename))
+	if name == "" || name == "." || name == string(filepath.Separator) {
+		name = "attachment"
+	}
+
+	// The content type that the browser sends is based on the file name, so it is detected from the contents instead
+	if _, err := b.persister.CreateAttachment(
+		r.Context(),
+
+		name,
+		http.DetectContentType(data),
+		data,
+
+		int32(journalEntryID),
+		userData.Email,
+	); err != nil {
+		if errors.Is(err, persisters.ErrJournalEntryDoesNotExist) {
+			log.Println(errInvalidForm, err)
+
+			http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+			return
+		}
+
+		log.Println(errCouldNotInsertIntoDB, err)
+
+		http.Error(w, errCouldNotInsertIntoDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	http.Redirect(w, r, "/journal/edit?id="+rjournalEntryID, http.StatusFound)
+}
+
+func (b *Controller) HandleDeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		log.Println(errCouldNotParseForm, err)
+
+		http.Error(w, errCouldNotParseForm.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	rid := r.FormValue("id")
+	if strings.TrimSpace(rid) == "" {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	id, err := strconv.Atoi(rid)
+	if err != nil {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	rjournalEntryID := r.FormValue("journal_entry_id")
+	if strings.TrimSpace(rjournalEntryID) == "" {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	if _, err := strconv.Atoi(rjournalEntryID); err != nil {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	if err := b.persister.DeleteAttachment(r.Context(), int32(id), userData.Email); err != nil {
+		log.Println(errCouldNotDeleteFromDB, err)
+
+		http.Error(w, errCouldNotDeleteFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	http.Redirect(w, r, "/journal/edit?id="+rjournalEntryID, http.StatusFound)
+}
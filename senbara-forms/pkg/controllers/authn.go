@@ -291,6 +291,8 @@ func (b *Controller) HandleAuthorize(w http.ResponseWriter, r *http.Request) {
 			MaxAge: -1,
 		})
 
+		b.deleteJournalUnlockSession(w, r)
+
 		if err := b.tpl.ExecuteTemplate(w, "redirect.html", redirectData{
 			pageData: pageData{
 				userData: userData{
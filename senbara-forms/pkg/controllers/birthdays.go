@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+)
+
+const (
+	defaultUpcomingBirthdaysDays = 30
+	maxUpcomingBirthdaysDays     = 366
+)
+
+type upcomingBirthday struct {
+	Contact models.Contact
+	Date    time.Time
+	Age     int
+	Days    int
+}
+
+type birthdaysData struct {
+	pageData
+	Entries []upcomingBirthday
+	Days    int
+}
+
+// getToday returns the current date in a location as midnight UTC, which is how
+// dates like birthdays are stored.
+func getToday(location *time.Location) time.Time {
+	now := time.Now().In(location)
+
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// getBirthdayInYear returns the date on which a birthday is celebrated in a year. People
+// born on February 29 celebrate on February 28 in years that aren't leap years.
+func getBirthdayInYear(birthday time.Time, year int) time.Time {
+	day := birthday.Day()
+	if birthday.Month() == time.February && day == 29 && !isLeapYear(year) {
+		day = 28
+	}
+
+	return time.Date(year, birthday.Month(), day, 0, 0, 0, 0, time.UTC)
+}
+
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+func getAge(birthday, today time.Time) int {
+	age := today.Year() - birthday.Year()
+	if today.Before(getBirthdayInYear(birthday, today.Year())) {
+		age--
+	}
+
+	return age
+}
+
+func getNextBirthday(birthday, today time.Time) time.Time {
+	next := getBirthdayInYear(birthday, today.Year())
+	if next.Before(today) {
+		next = getBirthdayInYear(birthday, today.Year()+1)
+	}
+
+	return next
+}
+
+// getUpcomingBirthdays returns the birthdays of contacts within the next `days` days,
+// including today, sorted by how soon they are.
+func getUpcomingBirthdays(contacts []models.Contact, today time.Time, days int) []upcomingBirthday {
+	upcomingBirthdays := []upcomingBirthday{}
+	for _, contact := range contacts {
+		if !contact.Birthday.Valid {
+			continue
+		}
+
+		next := getNextBirthday(contact.Birthday.Time, today)
+
+		daysUntil := int(next.Sub(today).Hours() / 24)
+		if daysUntil >= days {
+			continue
+		}
+
+		upcomingBirthdays = append(upcomingBirthdays, upcomingBirthday{
+			Contact: contact,
+			Date:    next,
+			Age:     next.Year() - contact.Birthday.Time.Year(),
+			Days:    daysUntil,
+		})
+	}
+
+	slices.SortStableFunc(upcomingBirthdays, func(a, b upcomingBirthday) int {
+		return a.Days - b.Days
+	})
+
+	return upcomingBirthdays
+}
+
+func (b *Controller) HandleBirthdays(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	days := defaultUpcomingBirthdaysDays
+	if rdays := r.URL.Query().Get("days"); strings.TrimSpace(rdays) != "" {
+		days, err = strconv.Atoi(rdays)
+		if err != nil || days < 1 || days > maxUpcomingBirthdaysDays {
+			log.Println(errInvalidQueryParam)
+
+			http.Error(w, errInvalidQueryParam.Error(), http.StatusUnprocessableEntity)
+
+			return
+		}
+	}
+
+	location, err := b.getLocation(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	contacts, err := b.persister.GetContacts(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if err := b.tpl.ExecuteTemplate(w, "birthdays.html", birthdaysData{
+		pageData: pageData{
+			userData: userData,
+
+			Page:       userData.Locale.Get("Upcoming birthdays"),
+			PrivacyURL: b.privacyURL,
+			ImprintURL: b.imprintURL,
+
+			BackURL: "/contacts",
+		},
+		Entries: getUpcomingBirthdays(contacts, getToday(location), days),
+		Days:    days,
+	}); err != nil {
+		log.Println(errCouldNotRenderTemplate, err)
+
+		http.Error(w, errCouldNotRenderTemplate.Error(), http.StatusInternalServerError)
+
+		return
+	}
+}
@@ -0,0 +1,348 @@
+package controllers
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	_ "image/gif"
+)
+
+const (
+	maxContactPhotoUploadSize = 10 * 1024 * 1024
+	maxContactPhotoPixels     = 50 * 1000 * 1000
+)
+
+var (
+	// contactPhotoSizes are the edge lengths of the square thumbnails that are stored for each photo,
+	// ordered from largest to smallest so that each thumbnail can be downscaled from the previous one.
+	contactPhotoSizes = []int32{256, 128, 64}
+)
+
+// createContactPhotoThumbnails validates an uploaded photo, crops it to a centered square and
+// downscales it to all `contactPhotoSizes`. JPEGs stay JPEGs, all other supported types are
+// stored as PNGs so that transparency is kept.
+func createContactPhotoThumbnails(data []byte) (string, map[int32][]byte, error) {
+	contentType := http.DetectContentType(data)
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+	default:
+		return "", nil, errUnsupportedPhotoType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", nil, errors.Join(errCouldNotProcessPhoto, err)
+	}
+
+	if config.Width*config.Height > maxContactPhotoPixels {
+		return "", nil, errPhotoTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", nil, errors.Join(errCouldNotProcessPhoto, err)
+	}
+
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	if side <= 0 {
+		return "", nil, errCouldNotProcessPhoto
+	}
+
+	if subImager, ok := src.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		offset := image.Pt((bounds.Dx()-side)/2, (bounds.Dy()-side)/2)
+
+		src = subImager.SubImage(image.Rectangle{
+			Min: bounds.Min.Add(offset),
+			Max: bounds.Min.Add(offset).Add(image.Pt(side, side)),
+		})
+	}
+
+	if contentType != "image/jpeg" {
+		contentType = "image/png"
+	}
+
+	thumbnails := map[int32][]byte{}
+	for _, size := range contactPhotoSizes {
+		src = downscaleImage(src, min(int(size), side))
+
+		var buf bytes.Buffer
+		if contentType == "image/jpeg" {
+			if err := jpeg.Encode(&buf, src, &jpeg.Options{Quality: 85}); err != nil {
+				return "", nil, errors.Join(errCouldNotProcessPhoto, err)
+			}
+		} else {
+			if err := png.Encode(&buf, src); err != nil {
+				return "", nil, errors.Join(errCouldNotProcessPhoto, err)
+			}
+		}
+
+		thumbnails[size] = buf.Bytes()
+	}
+
+	return contentType, thumbnails, nil
+}
+
+// downscaleImage scales a square image down to `size` by averaging all source pixels
+// that map onto each destination pixel.
+func downscaleImage(src image.Image, size int) image.Image {
+	bounds := src.Bounds()
+	if bounds.Dx() <= size && bounds.Dy() <= size {
+		return src
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/size
+		y1 := max(bounds.Min.Y+(y+1)*bounds.Dy()/size, y0+1)
+
+		for x := 0; x < size; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/size
+			x1 := max(bounds.Min.X+(x+1)*bounds.Dx()/size, x0+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					sr, sg, sb, sa := src.At(sx, sy).RGBA()
+
+					r += uint64(sr)
+					g += uint64(sg)
+					b += uint64(sb)
+					a += uint64(sa)
+					n++
+				}
+			}
+
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+
+	return dst
+}
+
+func (b *Controller) HandleContactPhoto(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	rid := r.URL.Query().Get("id")
+	if strings.TrimSpace(rid) == "" {
+		log.Println(errInvalidQueryParam)
+
+		http.Error(w, errInvalidQueryParam.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	id, err := strconv.Atoi(rid)
+	if err != nil {
+		log.Println(errInvalidQueryParam)
+
+		http.Error(w, errInvalidQueryParam.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	rsize := r.URL.Query().Get("size")
+	if strings.TrimSpace(rsize) == "" {
+		log.Println(errInvalidQueryParam)
+
+		http.Error(w, errInvalidQueryParam.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	size, err := strconv.Atoi(rsize)
+	if err != nil || !slices.Contains(contactPhotoSizes, int32(size)) {
+		log.Println(errInvalidQueryParam)
+
+		http.Error(w, errInvalidQueryParam.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	photo, err := b.persister.GetContactPhoto(r.Context(), int32(size), int32(id), userData.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	// Photos are private to the namespace, so shared caches may not store them; since the URL of
+	// a contact's photo stays the same when it is replaced, browsers need to revalidate with the ETag
+	w.Header().Set("Content-Type", photo.ContentType)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Set("ETag", fmt.Sprintf(`"%v"`, photo.ID))
+
+	http.ServeContent(w, r, "", photo.CreatedAt, bytes.NewReader(photo.Data))
+}
+
+func (b *Controller) HandleUpdateContactPhoto(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxContactPhotoUploadSize)
+
+	if err := r.ParseMultipartForm(maxContactPhotoUploadSize); err != nil {
+		log.Println(errCouldNotParseForm, err)
+
+		http.Error(w, errCouldNotParseForm.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	rid := r.FormValue("id")
+	if strings.TrimSpace(rid) == "" {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	id, err := strconv.Atoi(rid)
+	if err != nil {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	file, _, err := r.FormFile("photo")
+	if err != nil {
+		log.Println(errCouldNotReadRequest, err)
+
+		http.Error(w, errCouldNotReadRequest.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		log.Println(errCouldNotReadRequest, err)
+
+		http.Error(w, errCouldNotReadRequest.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	contentType, thumbnails, err := createContactPhotoThumbnails(data)
+	if err != nil {
+		log.Println(errInvalidForm, err)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	if err := b.persister.UpdateContactPhoto(
+		r.Context(),
+
+		contentType,
+		thumbnails,
+
+		int32(id),
+		userData.Email,
+	); err != nil {
+		log.Println(errCouldNotUpdateInDB, err)
+
+		http.Error(w, errCouldNotUpdateInDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	http.Redirect(w, r, "/contacts/edit?id="+rid, http.StatusFound)
+}
+
+func (b *Controller) HandleDeleteContactPhoto(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		log.Println(errCouldNotParseForm, err)
+
+		http.Error(w, errCouldNotParseForm.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	rid := r.FormValue("id")
+	if strings.TrimSpace(rid) == "" {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	id, err := strconv.Atoi(rid)
+	if err != nil {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	if err := b.persister.DeleteContactPhoto(r.Context(), int32(id), userData.Email); err != nil {
+		log.Println(errCouldNotDeleteFromDB, err)
+
+		http.Error(w, errCouldNotDeleteFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	http.Redirect(w, r, "/contacts/edit?id="+rid, http.StatusFound)
+}
@@ -1,27 +1,42 @@
 package controllers
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"net/mail"
+	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/persisters"
 )
 
 type contactsData struct {
 	pageData
-	Entries []models.Contact
+	Entries    []models.Contact
+	Pagination pagination
 }
 
 type contactData struct {
 	pageData
-	Entry      models.Contact
-	Debts      []models.GetDebtsRow
-	Activities []models.GetActivitiesRow
+	Entry         models.Contact
+	Debts         []models.GetDebtsRow
+	Activities    []models.GetActivitiesRow
+	Relationships []models.GetRelationshipsRow
+	Mentions      []models.GetJournalEntriesMentioningContactRow
+	Contacts      []models.Contact
+	HasPhoto      bool
+	CustomFields  []contactCustomField
+	Status        models.ContactStatus
+	Frequencies   []string
+	Age           int
+	Conflict      bool
 }
 
 func (b *Controller) HandleContacts(w http.ResponseWriter, r *http.Request) {
@@ -36,7 +51,21 @@ func (b *Controller) HandleContacts(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	contacts, err := b.persister.GetContacts(r.Context(), userData.Email)
+	var cursor persisters.ContactsCursor
+	p, err := getPagination(r, []sortOption{
+		{Sort: persisters.ContactsSortName, Descending: false},
+		{Sort: persisters.ContactsSortUpdatedAt, Descending: true},
+	}, &cursor)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	// Pages that end before a cursor are fetched in the reverse order
+	contacts, err := b.persister.GetContactsPage(r.Context(), p.Sort, p.Descending != p.backwards(), cursor, pageSize+1, userData.Email)
 	if err != nil {
 		log.Println(errCouldNotFetchFromDB, err)
 
@@ -45,6 +74,21 @@ func (b *Controller) HandleContacts(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if contacts, err = paginate(&p, contacts, func(contact models.Contact) any {
+		return persisters.ContactsCursor{
+			ID:        contact.ID,
+			FirstName: contact.FirstName,
+			LastName:  contact.LastName,
+			UpdatedAt: contact.UpdatedAt,
+		}
+	}); err != nil {
+		log.Println(errCouldNotRenderTemplate, err)
+
+		http.Error(w, errCouldNotRenderTemplate.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
 	if err := b.tpl.ExecuteTemplate(w, "contacts.html", contactsData{
 		pageData: pageData{
 			userData: userData,
@@ -53,7 +97,8 @@ func (b *Controller) HandleContacts(w http.ResponseWriter, r *http.Request) {
 			PrivacyURL: b.privacyURL,
 			ImprintURL: b.imprintURL,
 		},
-		Entries: contacts,
+		Entries:    contacts,
+		Pagination: p,
 	}); err != nil {
 		log.Println(errCouldNotRenderTemplate, err)
 
@@ -75,12 +120,24 @@ func (b *Controller) HandleAddContact(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := b.tpl.ExecuteTemplate(w, "contacts_add.html", pageData{
-		userData: userData,
+	customFields, err := b.persister.GetCustomFields(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if err := b.tpl.ExecuteTemplate(w, "contacts_add.html", contactData{
+		pageData: pageData{
+			userData: userData,
 
-		Page:       userData.Locale.Get("Add a contact"),
-		PrivacyURL: b.privacyURL,
-		ImprintURL: b.imprintURL,
+			Page:       userData.Locale.Get("Add a contact"),
+			PrivacyURL: b.privacyURL,
+			ImprintURL: b.imprintURL,
+		},
+		CustomFields: getContactCustomFields(customFields, nil),
 	}); err != nil {
 		log.Println(errCouldNotRenderTemplate, err)
 
@@ -148,6 +205,24 @@ func (b *Controller) HandleCreateContact(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	customFields, err := b.persister.GetCustomFields(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	customFieldValues, err := parseCustomFieldValues(r, customFields)
+	if err != nil {
+		log.Println(errInvalidForm, err)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
 	id, err := b.persister.CreateContact(
 		r.Context(),
 		firstName,
@@ -156,6 +231,7 @@ func (b *Controller) HandleCreateContact(w http.ResponseWriter, r *http.Request)
 		email,
 		pronouns,
 		userData.Email,
+		customFieldValues,
 	)
 	if err != nil {
 		log.Println(errCouldNotInsertIntoDB, err)
@@ -274,6 +350,74 @@ func (b *Controller) HandleViewContact(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	relationships, err := b.persister.GetRelationships(r.Context(), int32(id), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	mentions, err := b.persister.GetJournalEntriesMentioningContact(r.Context(), b.getJournalKey(r, userData.Email), int32(id), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	photoSizes, err := b.persister.GetContactPhotoSizes(r.Context(), int32(id), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	customFields, err := b.persister.GetCustomFields(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	customFieldValues, err := b.persister.GetCustomFieldValues(r.Context(), int32(id), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	location, err := b.getLocation(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	var age int
+	if contact.Birthday.Valid {
+		age = getAge(contact.Birthday.Time, getToday(location))
+	}
+
+	contactStatus, err := b.persister.GetContactStatus(r.Context(), int32(id), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
 	if err := b.tpl.ExecuteTemplate(w, "contacts_view.html", contactData{
 		pageData: pageData{
 			userData: userData,
@@ -284,9 +428,15 @@ func (b *Controller) HandleViewContact(w http.ResponseWriter, r *http.Request) {
 
 			BackURL: "/contacts",
 		},
-		Entry:      contact,
-		Debts:      debts,
-		Activities: activities,
+		Entry:         contact,
+		Debts:         debts,
+		Activities:    activities,
+		Relationships: relationships,
+		Mentions:      mentions,
+		HasPhoto:      len(photoSizes) > 0,
+		CustomFields:  getContactCustomFields(customFields, customFieldValues),
+		Status:        contactStatus,
+		Age:           age,
 	}); err != nil {
 		log.Println(errCouldNotRenderTemplate, err)
 
@@ -296,6 +446,47 @@ func (b *Controller) HandleViewContact(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// HandleResolveContact redirects `[[Contact Name]]` links in journal entries to the matching contact,
+// or to the search if no contact has that name (yet)
+func (b *Controller) HandleResolveContact(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		log.Println(errInvalidQueryParam)
+
+		http.Error(w, errInvalidQueryParam.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	id, err := b.persister.GetContactIDByName(r.Context(), name, userData.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Redirect(w, r, "/search?q="+url.QueryEscape(name), http.StatusFound)
+
+			return
+		}
+
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/contacts/view?id=%v", id), http.StatusFound)
+}
+
 func (b *Controller) HandleUpdateContact(w http.ResponseWriter, r *http.Request) {
 	redirected, userData, status, err := b.authorize(w, r)
 	if err != nil {
@@ -392,9 +583,46 @@ func (b *Controller) HandleUpdateContact(w http.ResponseWriter, r *http.Request)
 
 	notes := r.FormValue("notes")
 
+	contactFrequency := r.FormValue("contact_frequency")
+	if contactFrequency != "" && !slices.Contains(persisters.ContactFrequencies, contactFrequency) {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	customFields, err := b.persister.GetCustomFields(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	customFieldValues, err := parseCustomFieldValues(r, customFields)
+	if err != nil {
+		log.Println(errInvalidForm, err)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	version, err := strconv.Atoi(r.FormValue("version"))
+	if err != nil {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
 	if err := b.persister.UpdateContact(
 		r.Context(),
 		int32(id),
+		int32(version),
 		firstName,
 		lastName,
 		nickname,
@@ -404,7 +632,23 @@ func (b *Controller) HandleUpdateContact(w http.ResponseWriter, r *http.Request)
 		birthday,
 		address,
 		notes,
+		contactFrequency,
+		customFieldValues,
 	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		if errors.Is(err, persisters.ErrVersionConflict) {
+			log.Println(errCouldNotUpdateInDB, err)
+
+			b.renderEditContact(w, r, userData, int32(id), true)
+
+			return
+		}
+
 		log.Println(errCouldNotUpdateInDB, err)
 
 		http.Error(w, errCouldNotInsertIntoDB.Error(), http.StatusInternalServerError)
@@ -445,7 +689,22 @@ func (b *Controller) HandleEditContact(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	contact, err := b.persister.GetContact(r.Context(), int32(id), userData.Email)
+	b.renderEditContact(w, r, userData, int32(id), false)
+}
+
+// renderEditContact renders the edit page with the current values of a contact. If `conflict` is
+// set, the page explains that the contact was changed while it was being edited.
+func (b *Controller) renderEditContact(w http.ResponseWriter, r *http.Request, userData userData, id int32, conflict bool) {
+	contact, err := b.persister.GetContact(r.Context(), id, userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	relationships, err := b.persister.GetRelationships(r.Context(), id, userData.Email)
 	if err != nil {
 		log.Println(errCouldNotFetchFromDB, err)
 
@@ -454,6 +713,46 @@ func (b *Controller) HandleEditContact(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	contacts, err := b.persister.GetContacts(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	photoSizes, err := b.persister.GetContactPhotoSizes(r.Context(), id, userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	customFields, err := b.persister.GetCustomFields(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	customFieldValues, err := b.persister.GetCustomFieldValues(r.Context(), id, userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if conflict {
+		w.WriteHeader(http.StatusConflict)
+	}
+
 	if err := b.tpl.ExecuteTemplate(w, "contacts_edit.html", contactData{
 		pageData: pageData{
 			userData: userData,
@@ -462,7 +761,13 @@ func (b *Controller) HandleEditContact(w http.ResponseWriter, r *http.Request) {
 			PrivacyURL: b.privacyURL,
 			ImprintURL: b.imprintURL,
 		},
-		Entry: contact,
+		Entry:         contact,
+		Relationships: relationships,
+		Contacts:      contacts,
+		HasPhoto:      len(photoSizes) > 0,
+		CustomFields:  getContactCustomFields(customFields, customFieldValues),
+		Frequencies:   persisters.ContactFrequencies,
+		Conflict:      conflict,
 	}); err != nil {
 		log.Println(errCouldNotRenderTemplate, err)
 
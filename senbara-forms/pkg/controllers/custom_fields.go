@@ -0,0 +1,266 @@
+package controllers
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/persisters"
+)
+
+type customFieldsData struct {
+	pageData
+	Entries []models.CustomField
+	Kinds   []string
+}
+
+type contactCustomField struct {
+	models.CustomField
+	Value string
+}
+
+func getContactCustomFields(customFields []models.CustomField, customFieldValues []models.GetCustomFieldValuesRow) []contactCustomField {
+	values := map[int32]string{}
+	for _, customFieldValue := range customFieldValues {
+		values[customFieldValue.CustomFieldID] = customFieldValue.Value
+	}
+
+	contactCustomFields := []contactCustomField{}
+	for _, customField := range customFields {
+		contactCustomFields = append(contactCustomFields, contactCustomField{
+			CustomField: customField,
+			Value:       values[customField.ID],
+		})
+	}
+
+	return contactCustomFields
+}
+
+func parseCustomFieldValue(customField models.CustomField, value string) error {
+	switch customField.Kind {
+	case persisters.CustomFieldKindNumber:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+
+		if math.IsNaN(number) || math.IsInf(number, 0) {
+			return errInvalidForm
+		}
+
+	case persisters.CustomFieldKindDate:
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return err
+		}
+
+	case persisters.CustomFieldKindURL:
+		u, err := url.Parse(value)
+		if err != nil {
+			return err
+		}
+
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errInvalidForm
+		}
+
+	case persisters.CustomFieldKindSelect:
+		if !slices.Contains(customField.Options, value) {
+			return errInvalidForm
+		}
+	}
+
+	return nil
+}
+
+// parseCustomFieldValues reads and validates the values of all custom fields of a namespace
+// from a contact form. Empty values are kept so that they clear the stored value.
+func parseCustomFieldValues(r *http.Request, customFields []models.CustomField) (map[int32]string, error) {
+	customFieldValues := map[int32]string{}
+	for _, customField := range customFields {
+		value := strings.TrimSpace(r.FormValue(fmt.Sprintf("custom_field_%v", customField.ID)))
+		if value != "" {
+			if err := parseCustomFieldValue(customField, value); err != nil {
+				return nil, err
+			}
+		}
+
+		customFieldValues[customField.ID] = value
+	}
+
+	return customFieldValues, nil
+}
+
+func (b *Controller) HandleCustomFields(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	customFields, err := b.persister.GetCustomFields(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if err := b.tpl.ExecuteTemplate(w, "contacts_fields.html", customFieldsData{
+		pageData: pageData{
+			userData: userData,
+
+			Page:       userData.Locale.Get("Custom fields"),
+			PrivacyURL: b.privacyURL,
+			ImprintURL: b.imprintURL,
+
+			BackURL: "/contacts",
+		},
+		Entries: customFields,
+		Kinds:   persisters.CustomFieldKinds,
+	}); err != nil {
+		log.Println(errCouldNotRenderTemplate, err)
+
+		http.Error(w, errCouldNotRenderTemplate.Error(), http.StatusInternalServerError)
+
+		return
+	}
+}
+
+func (b *Controller) HandleCreateCustomField(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		log.Println(errCouldNotParseForm, err)
+
+		http.Error(w, errCouldNotParseForm.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	kind := r.FormValue("kind")
+	if !slices.Contains(persisters.CustomFieldKinds, kind) {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	options := []string{}
+	if kind == persisters.CustomFieldKindSelect {
+		for _, option := range strings.Split(r.FormValue("options"), "\n") {
+			option = strings.TrimSpace(option)
+			if option == "" || slices.Contains(options, option) {
+				continue
+			}
+
+			options = append(options, option)
+		}
+
+		if len(options) == 0 {
+			log.Println(errInvalidForm)
+
+			http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+			return
+		}
+	}
+
+	if _, err := b.persister.CreateCustomField(
+		r.Context(),
+
+		name,
+		kind,
+		options,
+
+		userData.Email,
+	); err != nil {
+		log.Println(errCouldNotInsertIntoDB, err)
+
+		http.Error(w, errCouldNotInsertIntoDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	http.Redirect(w, r, "/contacts/fields", http.StatusFound)
+}
+
+func (b *Controller) HandleDeleteCustomField(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		log.Println(errCouldNotParseForm, err)
+
+		http.Error(w, errCouldNotParseForm.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	rid := r.FormValue("id")
+	if strings.TrimSpace(rid) == "" {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	id, err := strconv.Atoi(rid)
+	if err != nil {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	if err := b.persister.DeleteCustomField(r.Context(), int32(id), userData.Email); err != nil {
+		log.Println(errCouldNotDeleteFromDB, err)
+
+		http.Error(w, errCouldNotDeleteFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	http.Redirect(w, r, "/contacts/fields", http.StatusFound)
+}
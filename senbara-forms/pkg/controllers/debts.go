@@ -1,6 +1,8 @@
 package controllers
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"math"
@@ -9,11 +11,13 @@ import (
 	"strings"
 
 	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/persisters"
 )
 
 type debtData struct {
 	pageData
-	Entry models.GetDebtAndContactRow
+	Entry    models.GetDebtAndContactRow
+	Conflict bool
 }
 
 func (b *Controller) HandleAddDebt(w http.ResponseWriter, r *http.Request) {
@@ -367,10 +371,20 @@ func (b *Controller) HandleUpdateDebt(w http.ResponseWriter, r *http.Request) {
 
 	description := r.FormValue("description")
 
+	version, err := strconv.Atoi(r.FormValue("version"))
+	if err != nil {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
 	if err := b.persister.UpdateDebt(
 		r.Context(),
 
 		int32(id),
+		int32(version),
 
 		int32(contactID),
 		userData.Email,
@@ -379,6 +393,20 @@ func (b *Controller) HandleUpdateDebt(w http.ResponseWriter, r *http.Request) {
 		currency,
 		description,
 	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		if errors.Is(err, persisters.ErrVersionConflict) {
+			log.Println(errCouldNotUpdateInDB, err)
+
+			b.renderEditDebt(w, r, userData, int32(id), int32(contactID), true)
+
+			return
+		}
+
 		log.Println(errCouldNotUpdateInDB, err)
 
 		http.Error(w, errCouldNotUpdateInDB.Error(), http.StatusInternalServerError)
@@ -437,7 +465,13 @@ func (b *Controller) HandleEditDebt(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	debtAndContact, err := b.persister.GetDebtAndContact(r.Context(), int32(id), int32(contactID), userData.Email)
+	b.renderEditDebt(w, r, userData, int32(id), int32(contactID), false)
+}
+
+// renderEditDebt renders the edit page with the current values of a debt. If `conflict` is
+// set, the page explains that the debt was changed while it was being edited.
+func (b *Controller) renderEditDebt(w http.ResponseWriter, r *http.Request, userData userData, id, contactID int32, conflict bool) {
+	debtAndContact, err := b.persister.GetDebtAndContact(r.Context(), id, contactID, userData.Email)
 	if err != nil {
 		log.Println(errCouldNotFetchFromDB, err)
 
@@ -446,6 +480,10 @@ func (b *Controller) HandleEditDebt(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if conflict {
+		w.WriteHeader(http.StatusConflict)
+	}
+
 	if err := b.tpl.ExecuteTemplate(w, "debts_edit.html", debtData{
 		pageData: pageData{
 			userData: userData,
@@ -454,7 +492,8 @@ func (b *Controller) HandleEditDebt(w http.ResponseWriter, r *http.Request) {
 			PrivacyURL: b.privacyURL,
 			ImprintURL: b.imprintURL,
 		},
-		Entry: debtAndContact,
+		Entry:    debtAndContact,
+		Conflict: conflict,
 	}); err != nil {
 		log.Println(errCouldNotRenderTemplate, err)
 
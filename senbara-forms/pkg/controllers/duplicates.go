@@ -0,0 +1,393 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/persisters"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	duplicateReasonSameEmail   = "sameEmail"
+	duplicateReasonSameName    = "sameName"
+	duplicateReasonSimilarName = "similarName"
+
+	maxSimilarNameDistance  = 2
+	minSimilarNameRuneCount = 6
+)
+
+type duplicateContacts struct {
+	Contact      models.Contact
+	OtherContact models.Contact
+	Reasons      []string
+}
+
+type duplicatesData struct {
+	pageData
+	Entries []duplicateContacts
+}
+
+type mergeData struct {
+	pageData
+	Entry      models.Contact
+	OtherEntry models.Contact
+}
+
+func normalizeName(name string) string {
+	normalized, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), name)
+	if err != nil {
+		normalized = name
+	}
+
+	return strings.Join(strings.Fields(strings.ToLower(normalized)), " ")
+}
+
+func levenshteinDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+
+	previous := make([]int, len(br)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ar); i++ {
+		current := make([]int, len(br)+1)
+		current[0] = i
+
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous = current
+	}
+
+	return previous[len(br)]
+}
+
+// findDuplicateContacts compares every pair of contacts and returns those that share an email
+// address, have the same (possibly swapped) first and last name or only differ by a few letters.
+func findDuplicateContacts(contacts []models.Contact) []duplicateContacts {
+	duplicates := []duplicateContacts{}
+	for i, contact := range contacts {
+		email := strings.ToLower(strings.TrimSpace(contact.Email))
+		name := normalizeName(contact.FirstName + " " + contact.LastName)
+		swappedName := normalizeName(contact.LastName + " " + contact.FirstName)
+
+		for _, otherContact := range contacts[i+1:] {
+			reasons := []string{}
+
+			if otherEmail := strings.ToLower(strings.TrimSpace(otherContact.Email)); email != "" && email == otherEmail {
+				reasons = append(reasons, duplicateReasonSameEmail)
+			}
+
+			otherName := normalizeName(otherContact.FirstName + " " + otherContact.LastName)
+			if name == otherName || swappedName == otherName {
+				reasons = append(reasons, duplicateReasonSameName)
+			} else if min(len([]rune(name)), len([]rune(otherName))) >= minSimilarNameRuneCount &&
+				levenshteinDistance(name, otherName) <= maxSimilarNameDistance {
+				reasons = append(reasons, duplicateReasonSimilarName)
+			}
+
+			if len(reasons) > 0 {
+				duplicates = append(duplicates, duplicateContacts{
+					Contact:      contact,
+					OtherContact: otherContact,
+					Reasons:      reasons,
+				})
+			}
+		}
+	}
+
+	return duplicates
+}
+
+func (b *Controller) HandleDuplicateContacts(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	contacts, err := b.persister.GetContacts(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if err := b.tpl.ExecuteTemplate(w, "contacts_duplicates.html", duplicatesData{
+		pageData: pageData{
+			userData: userData,
+
+			Page:       userData.Locale.Get("Duplicate contacts"),
+			PrivacyURL: b.privacyURL,
+			ImprintURL: b.imprintURL,
+
+			BackURL: "/contacts",
+		},
+		Entries: findDuplicateContacts(contacts),
+	}); err != nil {
+		log.Println(errCouldNotRenderTemplate, err)
+
+		http.Error(w, errCouldNotRenderTemplate.Error(), http.StatusInternalServerError)
+
+		return
+	}
+}
+
+func (b *Controller) HandleEditMergeContacts(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	rid := r.URL.Query().Get("id")
+	if strings.TrimSpace(rid) == "" {
+		log.Println(errInvalidQueryParam)
+
+		http.Error(w, errInvalidQueryParam.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	id, err := strconv.Atoi(rid)
+	if err != nil {
+		log.Println(errInvalidQueryParam)
+
+		http.Error(w, errInvalidQueryParam.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	rotherID := r.URL.Query().Get("other_id")
+	if strings.TrimSpace(rotherID) == "" {
+		log.Println(errInvalidQueryParam)
+
+		http.Error(w, errInvalidQueryParam.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	otherID, err := strconv.Atoi(rotherID)
+	if err != nil || otherID == id {
+		log.Println(errInvalidQueryParam)
+
+		http.Error(w, errInvalidQueryParam.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	contact, err := b.persister.GetContact(r.Context(), int32(id), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	otherContact, err := b.persister.GetContact(r.Context(), int32(otherID), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if err := b.tpl.ExecuteTemplate(w, "contacts_merge.html", mergeData{
+		pageData: pageData{
+			userData: userData,
+
+			Page:       userData.Locale.Get("Merge contacts"),
+			PrivacyURL: b.privacyURL,
+			ImprintURL: b.imprintURL,
+
+			BackURL: "/contacts/duplicates",
+		},
+		Entry:      contact,
+		OtherEntry: otherContact,
+	}); err != nil {
+		log.Println(errCouldNotRenderTemplate, err)
+
+		http.Error(w, errCouldNotRenderTemplate.Error(), http.StatusInternalServerError)
+
+		return
+	}
+}
+
+func (b *Controller) HandleMergeContacts(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		log.Println(errCouldNotParseForm, err)
+
+		http.Error(w, errCouldNotParseForm.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	rid := r.FormValue("id")
+	if strings.TrimSpace(rid) == "" {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	id, err := strconv.Atoi(rid)
+	if err != nil {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	rotherID := r.FormValue("other_id")
+	if strings.TrimSpace(rotherID) == "" {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	otherID, err := strconv.Atoi(rotherID)
+	if err != nil || otherID == id {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	firstName := r.FormValue("first_name")
+	if strings.TrimSpace(firstName) == "" {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	lastName := r.FormValue("last_name")
+	if strings.TrimSpace(lastName) == "" {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	email := r.FormValue("email")
+	if _, err := mail.ParseAddress(email); err != nil {
+		log.Println(err)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	nickname := r.FormValue("nickname")
+
+	pronouns := r.FormValue("pronouns")
+	if strings.TrimSpace(pronouns) == "" {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	rbirthday := r.FormValue("birthday")
+
+	var birthday *time.Time
+	if strings.TrimSpace(rbirthday) != "" {
+		b, err := time.Parse("2006-01-02", rbirthday)
+		if err != nil {
+			log.Println(errInvalidForm)
+
+			http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+			return
+		}
+
+		birthday = &b
+	}
+
+	address := r.FormValue("address")
+
+	notes := r.FormValue("notes")
+
+	if err := b.persister.MergeContacts(
+		r.Context(),
+
+		int32(id),
+		int32(otherID),
+		userData.Email,
+
+		firstName,
+		lastName,
+		nickname,
+		email,
+		pronouns,
+		birthday,
+		address,
+		notes,
+	); err != nil {
+		if errors.Is(err, persisters.ErrCannotMergeContactWithItself) {
+			log.Println(errInvalidForm, err)
+
+			http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+			return
+		}
+
+		log.Println(errCouldNotUpdateInDB, err)
+
+		http.Error(w, errCouldNotUpdateInDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	http.Redirect(w, r, "/contacts/view?id="+rid, http.StatusFound)
+}
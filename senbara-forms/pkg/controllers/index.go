@@ -3,6 +3,13 @@ package controllers
 import (
 	"log"
 	"net/http"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+)
+
+const (
+	indexListLength            = 5
+	indexUpcomingBirthdaysDays = 14
 )
 
 func (b *Controller) HandleIndex(w http.ResponseWriter, r *http.Request) {
@@ -16,6 +23,38 @@ func (b *Controller) HandleIndex(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		var (
+			dashboard         models.Dashboard
+			upcomingBirthdays []upcomingBirthday
+		)
+		if userData.Email != "" {
+			location, err := b.getLocation(r.Context(), userData.Email)
+			if err != nil {
+				log.Println(errCouldNotFetchFromDB, err)
+
+				http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+				return
+			}
+
+			today := getToday(location)
+
+			dashboard, err = b.persister.GetDashboard(r.Context(), b.getJournalKey(r, userData.Email), today, indexUpcomingBirthdaysDays, indexListLength, userData.Email)
+			if err != nil {
+				log.Println(errCouldNotFetchFromDB, err)
+
+				http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+				return
+			}
+
+			for i, journalEntry := range dashboard.RecentJournalEntries {
+				dashboard.RecentJournalEntries[i].Date = journalEntry.Date.In(location)
+			}
+
+			upcomingBirthdays = getUpcomingBirthdays(dashboard.UpcomingBirthdays, today, indexUpcomingBirthdaysDays)
+		}
+
 		if err := b.tpl.ExecuteTemplate(w, "index.html", indexData{
 			pageData: pageData{
 				userData: userData,
@@ -24,6 +63,10 @@ func (b *Controller) HandleIndex(w http.ResponseWriter, r *http.Request) {
 				PrivacyURL: b.privacyURL,
 				ImprintURL: b.imprintURL,
 			},
+			Dashboard: dashboard,
+
+			UpcomingBirthdays:     upcomingBirthdays,
+			UpcomingBirthdaysDays: indexUpcomingBirthdaysDays,
 		}); err != nil {
 			log.Println(errCouldNotRenderTemplate, err)
 
@@ -1,23 +1,50 @@
 package controllers
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/persisters"
+)
+
+const (
+	journalEntryDateFormat = "2006-01-02T15:04"
 )
 
 type journalData struct {
 	pageData
-	Entries []models.JournalEntry
+	Entries    []models.JournalEntry
+	Pagination pagination
+	Tags       map[int32][]string
+	FilterTags []string
+
+	PreviewFormat string
 }
 
 type journalEntryData struct {
 	pageData
-	Entry models.JournalEntry
+	Entry       models.JournalEntry
+	Tags        []string
+	Attachments []models.Attachment
+	Conflict    bool
+}
+
+// parseJournalEntryDate parses the value of a `datetime-local` input in the user's time zone
+func parseJournalEntryDate(value string, location *time.Location) (time.Time, error) {
+	date, err := time.ParseInLocation(journalEntryDateFormat, value, location)
+	if err != nil {
+		// Browsers include seconds if they are set
+		return time.ParseInLocation(journalEntryDateFormat+":05", value, location)
+	}
+
+	return date, nil
 }
 
 func (b *Controller) HandleJournal(w http.ResponseWriter, r *http.Request) {
@@ -32,7 +59,88 @@ func (b *Controller) HandleJournal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	journalEntries, err := b.persister.GetJournalEntries(r.Context(), userData.Email)
+	redirected, journalKey, err := b.requireJournalKey(w, r, userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	var cursor persisters.JournalEntriesCursor
+	p, err := getPagination(r, []sortOption{
+		{Sort: persisters.JournalEntriesSortDate, Descending: true},
+		{Sort: persisters.JournalEntriesSortRating, Descending: true},
+	}, &cursor)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	filterTags := getJournalEntryTags(r.URL.Query()["tag"])
+	for _, tag := range filterTags {
+		p.Filters = append(p.Filters, queryFilter{
+			Key:   "tag",
+			Value: tag,
+		})
+	}
+
+	// Pages that end before a cursor are fetched in the reverse order
+	journalEntries, err := b.persister.GetJournalEntriesPage(r.Context(), journalKey, p.Sort, p.Descending != p.backwards(), filterTags, cursor, pageSize+1, userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if journalEntries, err = paginate(&p, journalEntries, func(journalEntry models.JournalEntry) any {
+		return persisters.JournalEntriesCursor{
+			ID:     journalEntry.ID,
+			Date:   journalEntry.Date,
+			Rating: journalEntry.Rating,
+		}
+	}); err != nil {
+		log.Println(errCouldNotRenderTemplate, err)
+
+		http.Error(w, errCouldNotRenderTemplate.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	location, err := b.getLocation(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	journalEntryIDs := []int32{}
+	for i, journalEntry := range journalEntries {
+		journalEntryIDs = append(journalEntryIDs, journalEntry.ID)
+
+		journalEntries[i].Date = journalEntry.Date.In(location)
+	}
+
+	tags, err := b.persister.GetJournalEntryTagsForJournalEntries(r.Context(), journalEntryIDs, userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	settings, err := b.persister.GetSettings(r.Context(), userData.Email)
 	if err != nil {
 		log.Println(errCouldNotFetchFromDB, err)
 
@@ -49,7 +157,12 @@ func (b *Controller) HandleJournal(w http.ResponseWriter, r *http.Request) {
 			PrivacyURL: b.privacyURL,
 			ImprintURL: b.imprintURL,
 		},
-		Entries: journalEntries,
+		Entries:    journalEntries,
+		Pagination: p,
+		Tags:       tags,
+		FilterTags: filterTags,
+
+		PreviewFormat: settings.PreviewFormat,
 	}); err != nil {
 		log.Println(errCouldNotRenderTemplate, err)
 
@@ -71,12 +184,38 @@ func (b *Controller) HandleAddJournal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := b.tpl.ExecuteTemplate(w, "journal_add.html", pageData{
-		userData: userData,
+	// The journal has to be unlocked before writing an entry that can't be saved otherwise
+	redirected, _, err = b.requireJournalKey(w, r, userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	} else if redirected {
+		return
+	}
 
-		Page:       userData.Locale.Get("Add a journal entry"),
-		PrivacyURL: b.privacyURL,
-		ImprintURL: b.imprintURL,
+	location, err := b.getLocation(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if err := b.tpl.ExecuteTemplate(w, "journal_add.html", journalEntryData{
+		pageData: pageData{
+			userData: userData,
+
+			Page:       userData.Locale.Get("Add a journal entry"),
+			PrivacyURL: b.privacyURL,
+			ImprintURL: b.imprintURL,
+		},
+		Entry: models.JournalEntry{
+			Date: time.Now().In(location),
+		},
 	}); err != nil {
 		log.Println(errCouldNotRenderTemplate, err)
 
@@ -98,6 +237,17 @@ func (b *Controller) HandleCreateJournal(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	redirected, journalKey, err := b.requireJournalKey(w, r, userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	} else if redirected {
+		return
+	}
+
 	if err := r.ParseForm(); err != nil {
 		log.Println(errCouldNotParseForm, err)
 
@@ -142,7 +292,27 @@ func (b *Controller) HandleCreateJournal(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	id, err := b.persister.CreateJournalEntry(r.Context(), title, body, int32(rating), userData.Email)
+	location, err := b.getLocation(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	date, err := parseJournalEntryDate(r.FormValue("date"), location)
+	if err != nil {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	tags := getJournalEntryTags(strings.Split(r.FormValue("tags"), ","))
+
+	id, err := b.persister.CreateJournalEntry(r.Context(), journalKey, title, body, int32(rating), date, tags, userData.Email)
 	if err != nil {
 		log.Println(errCouldNotInsertIntoDB, err)
 
@@ -215,6 +385,17 @@ func (b *Controller) HandleEditJournal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	redirected, journalKey, err := b.requireJournalKey(w, r, userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	} else if redirected {
+		return
+	}
+
 	rid := r.FormValue("id")
 	if strings.TrimSpace(rid) == "" {
 		log.Println(errInvalidQueryParam)
@@ -233,7 +414,13 @@ func (b *Controller) HandleEditJournal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	journalEntry, err := b.persister.GetJournalEntry(r.Context(), int32(id), userData.Email)
+	b.renderEditJournal(w, r, userData, journalKey, int32(id), false)
+}
+
+// renderEditJournal renders the edit page with the current values of a journal entry. If `conflict` is
+// set, the page explains that the entry was changed while it was being edited.
+func (b *Controller) renderEditJournal(w http.ResponseWriter, r *http.Request, userData userData, journalKey []byte, id int32, conflict bool) {
+	journalEntry, err := b.persister.GetJournalEntry(r.Context(), journalKey, id, userData.Email)
 	if err != nil {
 		log.Println(errCouldNotFetchFromDB, err)
 
@@ -242,6 +429,39 @@ func (b *Controller) HandleEditJournal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	tags, err := b.persister.GetJournalEntryTags(r.Context(), id, userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	attachments, err := b.persister.GetAttachments(r.Context(), id, userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	location, err := b.getLocation(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	journalEntry.Date = journalEntry.Date.In(location)
+
+	if conflict {
+		w.WriteHeader(http.StatusConflict)
+	}
+
 	if err := b.tpl.ExecuteTemplate(w, "journal_edit.html", journalEntryData{
 		pageData: pageData{
 			userData: userData,
@@ -250,7 +470,10 @@ func (b *Controller) HandleEditJournal(w http.ResponseWriter, r *http.Request) {
 			PrivacyURL: b.privacyURL,
 			ImprintURL: b.imprintURL,
 		},
-		Entry: journalEntry,
+		Entry:       journalEntry,
+		Tags:        tags,
+		Attachments: attachments,
+		Conflict:    conflict,
 	}); err != nil {
 		log.Println(errCouldNotRenderTemplate, err)
 
@@ -272,6 +495,17 @@ func (b *Controller) HandleUpdateJournal(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	redirected, journalKey, err := b.requireJournalKey(w, r, userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	} else if redirected {
+		return
+	}
+
 	if err := r.ParseForm(); err != nil {
 		log.Println(errCouldNotParseForm, err)
 
@@ -334,7 +568,50 @@ func (b *Controller) HandleUpdateJournal(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := b.persister.UpdateJournalEntry(r.Context(), int32(id), title, body, int32(rating), userData.Email); err != nil {
+	location, err := b.getLocation(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	date, err := parseJournalEntryDate(r.FormValue("date"), location)
+	if err != nil {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	tags := getJournalEntryTags(strings.Split(r.FormValue("tags"), ","))
+
+	version, err := strconv.Atoi(r.FormValue("version"))
+	if err != nil {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	if err := b.persister.UpdateJournalEntry(r.Context(), journalKey, int32(id), int32(version), title, body, int32(rating), date, tags, userData.Email); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		if errors.Is(err, persisters.ErrVersionConflict) {
+			log.Println(errCouldNotUpdateInDB, err)
+
+			b.renderEditJournal(w, r, userData, journalKey, int32(id), true)
+
+			return
+		}
+
 		log.Println(errCouldNotUpdateInDB, err)
 
 		http.Error(w, errCouldNotInsertIntoDB.Error(), http.StatusInternalServerError)
@@ -357,6 +634,17 @@ func (b *Controller) HandleViewJournal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	redirected, journalKey, err := b.requireJournalKey(w, r, userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	} else if redirected {
+		return
+	}
+
 	rid := r.FormValue("id")
 	if strings.TrimSpace(rid) == "" {
 		log.Println(errInvalidQueryParam)
@@ -375,7 +663,34 @@ func (b *Controller) HandleViewJournal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	journalEntry, err := b.persister.GetJournalEntry(r.Context(), int32(id), userData.Email)
+	journalEntry, err := b.persister.GetJournalEntry(r.Context(), journalKey, int32(id), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	tags, err := b.persister.GetJournalEntryTags(r.Context(), int32(id), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	attachments, err := b.persister.GetAttachments(r.Context(), int32(id), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	location, err := b.getLocation(r.Context(), userData.Email)
 	if err != nil {
 		log.Println(errCouldNotFetchFromDB, err)
 
@@ -384,6 +699,8 @@ func (b *Controller) HandleViewJournal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	journalEntry.Date = journalEntry.Date.In(location)
+
 	if err := b.tpl.ExecuteTemplate(w, "journal_view.html", journalEntryData{
 		pageData: pageData{
 			userData: userData,
@@ -394,7 +711,9 @@ func (b *Controller) HandleViewJournal(w http.ResponseWriter, r *http.Request) {
 
 			BackURL: "/journal",
 		},
-		Entry: journalEntry,
+		Entry:       journalEntry,
+		Tags:        tags,
+		Attachments: attachments,
 	}); err != nil {
 		log.Println(errCouldNotRenderTemplate, err)
 
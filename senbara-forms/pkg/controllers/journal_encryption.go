@@ -0,0 +1,377 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/persisters"
+)
+
+const (
+	journalUnlockKey        = "journal_unlock"
+	journalUnlockSessionTTL = 15 * time.Minute
+
+	minJournalPassphraseLength = 8
+)
+
+type journalEncryptionData struct {
+	pageData
+	Enabled           bool
+	Unlocked          bool
+	InvalidPassphrase bool
+
+	MinPassphraseLength int
+}
+
+// journalUnlockSession holds the key of an unlocked journal. Sessions are only kept in memory,
+// so the key never leaves the server process and all sessions are locked again on restarts.
+type journalUnlockSession struct {
+	namespace string
+	key       []byte
+	expiresAt time.Time
+}
+
+// getJournalKey returns the key of the unlock session of a request, or nil if the journal isn't unlocked
+func (b *Controller) getJournalKey(r *http.Request, namespace string) []byte {
+	cookie, err := r.Cookie(journalUnlockKey)
+	if err != nil {
+		return nil
+	}
+
+	b.journalUnlockSessionsLock.Lock()
+	defer b.journalUnlockSessionsLock.Unlock()
+
+	session, ok := b.journalUnlockSessions[cookie.Value]
+	if !ok || session.namespace != namespace {
+		return nil
+	}
+
+	if time.Now().After(session.expiresAt) {
+		delete(b.journalUnlockSessions, cookie.Value)
+
+		return nil
+	}
+
+	return session.key
+}
+
+// requireJournalKey returns the key of the unlock session of a request. If the journal of the
+// user is encrypted but not unlocked, it redirects to the page where it can be unlocked.
+func (b *Controller) requireJournalKey(w http.ResponseWriter, r *http.Request, namespace string) (bool, []byte, error) {
+	key := b.getJournalKey(r, namespace)
+
+	unlocked, err := b.persister.IsJournalUnlocked(r.Context(), key, namespace)
+	if err != nil {
+		return false, nil, err
+	}
+
+	if !unlocked {
+		http.Redirect(w, r, "/journal/encryption", http.StatusFound)
+
+		return true, nil, nil
+	}
+
+	return false, key, nil
+}
+
+func (b *Controller) createJournalUnlockSession(w http.ResponseWriter, namespace string, key []byte) error {
+	rawID := make([]byte, 32)
+	if _, err := rand.Read(rawID); err != nil {
+		return err
+	}
+
+	id := hex.EncodeToString(rawID)
+	expiresAt := time.Now().Add(journalUnlockSessionTTL)
+
+	b.journalUnlockSessionsLock.Lock()
+	defer b.journalUnlockSessionsLock.Unlock()
+
+	// Expired sessions are otherwise only removed when they are used again
+	for id, session := range b.journalUnlockSessions {
+		if time.Now().After(session.expiresAt) {
+			delete(b.journalUnlockSessions, id)
+		}
+	}
+
+	b.journalUnlockSessions[id] = journalUnlockSession{
+		namespace: namespace,
+		key:       key,
+		expiresAt: expiresAt,
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     journalUnlockKey,
+		Value:    id,
+		Expires:  expiresAt,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+		Path:     "/",
+	})
+
+	return nil
+}
+
+// deleteJournalUnlockSessions locks the journal of a namespace again in all sessions
+func (b *Controller) deleteJournalUnlockSessions(w http.ResponseWriter, namespace string) {
+	b.journalUnlockSessionsLock.Lock()
+	defer b.journalUnlockSessionsLock.Unlock()
+
+	for id, session := range b.journalUnlockSessions {
+		if session.namespace == namespace {
+			delete(b.journalUnlockSessions, id)
+		}
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:   journalUnlockKey,
+		Value:  "",
+		MaxAge: -1,
+		Path:   "/",
+	})
+}
+
+// deleteJournalUnlockSession locks the journal again in the session of a request, e.g. when signing out
+func (b *Controller) deleteJournalUnlockSession(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(journalUnlockKey)
+	if err != nil {
+		return
+	}
+
+	b.journalUnlockSessionsLock.Lock()
+	delete(b.journalUnlockSessions, cookie.Value)
+	b.journalUnlockSessionsLock.Unlock()
+
+	http.SetCookie(w, &http.Cookie{
+		Name:   journalUnlockKey,
+		Value:  "",
+		MaxAge: -1,
+		Path:   "/",
+	})
+}
+
+func (b *Controller) HandleJournalEncryption(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	b.renderJournalEncryption(w, r, userData, false)
+}
+
+func (b *Controller) renderJournalEncryption(w http.ResponseWriter, r *http.Request, userData userData, invalidPassphrase bool) {
+	settings, err := b.persister.GetSettings(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	unlocked, err := b.persister.IsJournalUnlocked(r.Context(), b.getJournalKey(r, userData.Email), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if invalidPassphrase {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+
+	if err := b.tpl.ExecuteTemplate(w, "journal_encryption.html", journalEncryptionData{
+		pageData: pageData{
+			userData: userData,
+
+			Page:       userData.Locale.Get("Journal encryption"),
+			PrivacyURL: b.privacyURL,
+			ImprintURL: b.imprintURL,
+
+			BackURL: "/journal",
+		},
+		Enabled:           settings.JournalEncrypted,
+		Unlocked:          unlocked,
+		InvalidPassphrase: invalidPassphrase,
+
+		MinPassphraseLength: minJournalPassphraseLength,
+	}); err != nil {
+		log.Println(errCouldNotRenderTemplate, err)
+
+		http.Error(w, errCouldNotRenderTemplate.Error(), http.StatusInternalServerError)
+
+		return
+	}
+}
+
+func (b *Controller) HandleEnableJournalEncryption(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		log.Println(errCouldNotParseForm, err)
+
+		http.Error(w, errCouldNotParseForm.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	// Passphrases aren't trimmed since whitespace may be part of them
+	passphrase := r.FormValue("passphrase")
+	if len([]rune(passphrase)) < minJournalPassphraseLength || passphrase != r.FormValue("passphrase_confirmation") {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	key, err := b.persister.EnableJournalEncryption(r.Context(), passphrase, userData.Email)
+	if err != nil {
+		if errors.Is(err, persisters.ErrJournalEncryptionEnabled) {
+			log.Println(errInvalidForm, err)
+
+			http.Error(w, errInvalidForm.Error(), http.StatusConflict)
+
+			return
+		}
+
+		log.Println(errCouldNotUpdateInDB, err)
+
+		http.Error(w, errCouldNotUpdateInDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if err := b.createJournalUnlockSession(w, userData.Email, key); err != nil {
+		log.Println(errCouldNotUnlockJournal, err)
+
+		http.Error(w, errCouldNotUnlockJournal.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	http.Redirect(w, r, "/journal/encryption", http.StatusFound)
+}
+
+func (b *Controller) HandleDisableJournalEncryption(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	if err := b.persister.DisableJournalEncryption(r.Context(), b.getJournalKey(r, userData.Email), userData.Email); err != nil {
+		if errors.Is(err, persisters.ErrJournalLocked) || errors.Is(err, persisters.ErrJournalEncryptionDisabled) {
+			http.Redirect(w, r, "/journal/encryption", http.StatusFound)
+
+			return
+		}
+
+		log.Println(errCouldNotUpdateInDB, err)
+
+		http.Error(w, errCouldNotUpdateInDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	b.deleteJournalUnlockSessions(w, userData.Email)
+
+	http.Redirect(w, r, "/journal/encryption", http.StatusFound)
+}
+
+func (b *Controller) HandleUnlockJournal(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		log.Println(errCouldNotParseForm, err)
+
+		http.Error(w, errCouldNotParseForm.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	key, err := b.persister.UnlockJournal(r.Context(), r.FormValue("passphrase"), userData.Email)
+	if err != nil {
+		if errors.Is(err, persisters.ErrInvalidPassphrase) {
+			log.Println(errCouldNotUnlockJournal, err)
+
+			b.renderJournalEncryption(w, r, userData, true)
+
+			return
+		}
+
+		if errors.Is(err, persisters.ErrJournalEncryptionDisabled) {
+			http.Redirect(w, r, "/journal", http.StatusFound)
+
+			return
+		}
+
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if err := b.createJournalUnlockSession(w, userData.Email, key); err != nil {
+		log.Println(errCouldNotUnlockJournal, err)
+
+		http.Error(w, errCouldNotUnlockJournal.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	http.Redirect(w, r, "/journal", http.StatusFound)
+}
+
+func (b *Controller) HandleLockJournal(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	b.deleteJournalUnlockSessions(w, userData.Email)
+
+	http.Redirect(w, r, "/journal/encryption", http.StatusFound)
+}
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+)
+
+type journalTagsData struct {
+	pageData
+	Entries []models.GetJournalTagsRow
+}
+
+// getJournalEntryTags trims and lowercases free-form tags and drops empty and duplicate ones
+func getJournalEntryTags(rtags []string) []string {
+	tags := []string{}
+	for _, rtag := range rtags {
+		tag := strings.ToLower(strings.TrimSpace(rtag))
+		if tag == "" || slices.Contains(tags, tag) {
+			continue
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
+func (b *Controller) HandleJournalTags(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	tags, err := b.persister.GetJournalTags(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if err := b.tpl.ExecuteTemplate(w, "journal_tags.html", journalTagsData{
+		pageData: pageData{
+			userData: userData,
+
+			Page:       userData.Locale.Get("Tags"),
+			PrivacyURL: b.privacyURL,
+			ImprintURL: b.imprintURL,
+
+			BackURL: "/journal",
+		},
+		Entries: tags,
+	}); err != nil {
+		log.Println(errCouldNotRenderTemplate, err)
+
+		http.Error(w, errCouldNotRenderTemplate.Error(), http.StatusInternalServerError)
+
+		return
+	}
+}
@@ -0,0 +1,440 @@
+package controllers
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/frontmatter"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+)
+
+const (
+	maxJournalMarkdownImportSize = 50 * 1024 * 1024
+	maxJournalMarkdownFileSize   = 4 * 1024 * 1024
+
+	maxJournalMarkdownSlugLength = 50
+
+	// defaultImportedJournalEntryRating is used for imported journal entries without a rating, e.g. from Obsidian
+	defaultImportedJournalEntryRating = 2
+
+	journalMarkdownFileNameDateFormat = "2006-01-02"
+)
+
+var (
+	// journalMarkdownDateFormats are the date formats used by Jekyll, Obsidian and most other tools
+	journalMarkdownDateFormats = []string{
+		time.RFC3339,
+		"2006-01-02 15:04:05 -0700",
+		"2006-01-02 15:04:05 -07:00",
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006-01-02T15:04",
+		"2006-01-02 15:04",
+		journalMarkdownFileNameDateFormat,
+	}
+
+	journalMarkdownFileNameDatePrefix = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}[-_ ]?`)
+
+	journalMarkdownExtensions = []string{".md", ".markdown"}
+)
+
+type importedJournalEntry struct {
+	title  string
+	body   string
+	rating int32
+	date   time.Time
+	tags   []string
+}
+
+// getJournalEntrySlug returns a file name-safe version of a journal entry's title
+func getJournalEntrySlug(title string) string {
+	var slug strings.Builder
+
+	dash := false
+	for _, r := range normalizeName(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			slug.WriteRune(r)
+
+			dash = false
+		} else if !dash && slug.Len() > 0 {
+			slug.WriteRune('-')
+
+			dash = true
+		}
+	}
+
+	runes := []rune(strings.TrimSuffix(slug.String(), "-"))
+	if len(runes) > maxJournalMarkdownSlugLength {
+		runes = []rune(strings.TrimSuffix(string(runes[:maxJournalMarkdownSlugLength]), "-"))
+	}
+
+	if len(runes) == 0 {
+		return "entry"
+	}
+
+	return string(runes)
+}
+
+// parseJournalMarkdownDate parses a date in the user's time zone, unless the date has an offset
+func parseJournalMarkdownDate(value string, location *time.Location) (time.Time, error) {
+	for _, format := range journalMarkdownDateFormats {
+		if date, err := time.ParseInLocation(format, value, location); err == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("could not parse date %q", value)
+}
+
+// parseJournalMarkdown parses a Markdown file with YAML front matter into a journal entry. Values that
+// are missing from the front matter are taken from the file name if possible, which is what Jekyll does.
+func parseJournalMarkdown(name string, modified time.Time, source []byte, location *time.Location) (importedJournalEntry, error) {
+	fields, body, err := frontmatter.Parse(source)
+	if err != nil {
+		return importedJournalEntry{}, err
+	}
+
+	baseName := strings.TrimSuffix(path.Base(name), path.Ext(name))
+
+	journalEntry := importedJournalEntry{
+		title:  strings.TrimSpace(fields["title"].Scalar),
+		body:   strings.TrimSpace(body),
+		rating: defaultImportedJournalEntryRating,
+		date:   modified,
+	}
+
+	// Archives don't have to contain modification times
+	if journalEntry.date.IsZero() {
+		journalEntry.date = time.Now()
+	}
+
+	if rdate := fields["date"].Scalar; rdate != "" {
+		if journalEntry.date, err = parseJournalMarkdownDate(rdate, location); err != nil {
+			return importedJournalEntry{}, err
+		}
+	} else if prefix := journalMarkdownFileNameDatePrefix.FindString(baseName); prefix != "" {
+		if journalEntry.date, err = time.ParseInLocation(journalMarkdownFileNameDateFormat, prefix[:len(journalMarkdownFileNameDateFormat)], location); err != nil {
+			return importedJournalEntry{}, err
+		}
+	}
+
+	if journalEntry.title == "" {
+		journalEntry.title = strings.TrimSpace(journalMarkdownFileNameDatePrefix.ReplaceAllString(baseName, ""))
+
+		// Jekyll uses slugs as file names, while Obsidian uses the title as it is
+		if !strings.Contains(journalEntry.title, " ") {
+			journalEntry.title = strings.NewReplacer("-", " ", "_", " ").Replace(journalEntry.title)
+		}
+
+		// Daily notes only have a date as their name
+		if journalEntry.title == "" {
+			journalEntry.title = journalEntry.date.In(location).Format(journalMarkdownFileNameDateFormat)
+		}
+	}
+
+	if journalEntry.body == "" {
+		return importedJournalEntry{}, errors.New("body is empty")
+	}
+
+	if rrating := fields["rating"].Scalar; rrating != "" {
+		rating, err := strconv.Atoi(rrating)
+		if err != nil || rating < 1 || rating > 3 {
+			return importedJournalEntry{}, fmt.Errorf("rating %q is not between 1 and 3", rrating)
+		}
+
+		journalEntry.rating = int32(rating)
+	}
+
+	rtags := fields["tags"].Strings()
+	for i, rtag := range rtags {
+		// Obsidian allows prefixing tags with a hash
+		rtags[i] = strings.TrimPrefix(strings.TrimSpace(rtag), "#")
+	}
+	journalEntry.tags = getJournalEntryTags(rtags)
+
+	return journalEntry, nil
+}
+
+// isJournalMarkdownFile reports whether a file in an imported archive is a journal entry. Hidden
+// files and directories, e.g. the configuration of an Obsidian vault, are skipped.
+func isJournalMarkdownFile(file *zip.File) bool {
+	if file.FileInfo().IsDir() || !slices.Contains(journalMarkdownExtensions, strings.ToLower(path.Ext(file.Name))) {
+		return false
+	}
+
+	for _, segment := range strings.Split(file.Name, "/") {
+		if strings.HasPrefix(segment, ".") || segment == "__MACOSX" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func readJournalMarkdownFile(file *zip.File) ([]byte, error) {
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// The uncompressed size in the header can't be trusted
+	source, err := io.ReadAll(io.LimitReader(f, maxJournalMarkdownFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(source) > maxJournalMarkdownFileSize {
+		return nil, errors.New("file is too large")
+	}
+
+	return source, nil
+}
+
+func (b *Controller) HandleExportJournal(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	redirected, journalKey, err := b.requireJournalKey(w, r, userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	location, err := b.getLocation(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	journalEntries, err := b.persister.GetJournalEntries(r.Context(), journalKey, userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	ids := []int32{}
+	for _, journalEntry := range journalEntries {
+		ids = append(ids, journalEntry.ID)
+	}
+
+	tags, err := b.persister.GetJournalEntryTagsForJournalEntries(r.Context(), ids, userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="senbara-forms-journal.zip"`)
+
+	archive := zip.NewWriter(w)
+
+	fileNames := map[string]struct{}{}
+	for _, journalEntry := range journalEntries {
+		date := journalEntry.Date.In(location)
+
+		// Entries with the same date and title would otherwise overwrite each other when extracted
+		baseName := date.Format(journalMarkdownFileNameDateFormat) + "-" + getJournalEntrySlug(journalEntry.Title)
+		fileName := baseName + ".md"
+		for i := 2; ; i++ {
+			if _, ok := fileNames[fileName]; !ok {
+				break
+			}
+
+			fileName = fmt.Sprintf("%v-%v.md", baseName, i)
+		}
+		fileNames[fileName] = struct{}{}
+
+		file, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     fileName,
+			Method:   zip.Deflate,
+			Modified: date,
+		})
+		if err != nil {
+			log.Println(errCouldNotWriteResponse, err)
+
+			http.Error(w, errCouldNotWriteResponse.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		entryTags := tags[journalEntry.ID]
+		if entryTags == nil {
+			entryTags = []string{}
+		}
+
+		if err := frontmatter.Write(file, []frontmatter.Field{
+			{Key: "title", Value: journalEntry.Title},
+			{Key: "date", Value: date},
+			{Key: "rating", Value: journalEntry.Rating},
+			{Key: "tags", Value: entryTags},
+		}, journalEntry.Body); err != nil {
+			log.Println(errCouldNotWriteResponse, err)
+
+			http.Error(w, errCouldNotWriteResponse.Error(), http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		log.Println(errCouldNotWriteResponse, err)
+
+		http.Error(w, errCouldNotWriteResponse.Error(), http.StatusInternalServerError)
+
+		return
+	}
+}
+
+func (b *Controller) HandleImportJournal(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	redirected, journalKey, err := b.requireJournalKey(w, r, userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxJournalMarkdownImportSize)
+
+	if err := r.ParseMultipartForm(maxJournalMarkdownImportSize); err != nil {
+		log.Println(errCouldNotParseForm, err)
+
+		http.Error(w, errCouldNotParseForm.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	file, header, err := r.FormFile("journal")
+	if err != nil {
+		log.Println(errCouldNotReadRequest, err)
+
+		http.Error(w, errCouldNotReadRequest.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+	defer file.Close()
+
+	archive, err := zip.NewReader(file, header.Size)
+	if err != nil {
+		log.Println(errInvalidJournalMarkdown, err)
+
+		http.Error(w, errInvalidJournalMarkdown.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	location, err := b.getLocation(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	// All files are parsed before the journal entries are created in one transaction so that invalid archives aren't imported partially
+	journalEntries := []models.ExportedJournalEntry{}
+	for _, f := range archive.File {
+		if !isJournalMarkdownFile(f) {
+			continue
+		}
+
+		source, err := readJournalMarkdownFile(f)
+		if err != nil {
+			err := fmt.Errorf("%w: %v: %w", errInvalidJournalMarkdown, f.Name, err)
+
+			log.Println(err)
+
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+
+			return
+		}
+
+		journalEntry, err := parseJournalMarkdown(f.Name, f.Modified, source, location)
+		if err != nil {
+			err := fmt.Errorf("%w: %v: %w", errInvalidJournalMarkdown, f.Name, err)
+
+			log.Println(err)
+
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+
+			return
+		}
+
+		journalEntries = append(journalEntries, models.ExportedJournalEntry{
+			Title:  journalEntry.title,
+			Body:   journalEntry.body,
+			Rating: journalEntry.rating,
+			Date:   journalEntry.date,
+			Tags:   journalEntry.tags,
+		})
+	}
+
+	if len(journalEntries) == 0 {
+		err := fmt.Errorf("%w: archive doesn't contain any Markdown files", errInvalidJournalMarkdown)
+
+		log.Println(err)
+
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	if err := b.persister.CreateJournalEntries(r.Context(), journalKey, journalEntries, userData.Email); err != nil {
+		log.Println(errCouldNotInsertIntoDB, err)
+
+		http.Error(w, errCouldNotInsertIntoDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	http.Redirect(w, r, "/journal", http.StatusFound)
+}
@@ -0,0 +1,332 @@
+package controllers
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+)
+
+const (
+	journalStatsPeriodWeek  = "week"
+	journalStatsPeriodMonth = "month"
+
+	ratingChartPeriods   = 12
+	ratingChartBarWidth  = 40
+	ratingChartBarGap    = 16
+	ratingChartBarHeight = 160
+	ratingChartLabelSize = 20
+
+	heatmapWeeks    = 53
+	heatmapCellSize = 12
+	heatmapCellGap  = 2
+)
+
+var (
+	journalStatsPeriods = []string{journalStatsPeriodWeek, journalStatsPeriodMonth}
+)
+
+type ratingChartSegment struct {
+	Rating int32
+	Count  int
+	Y      int
+	Height int
+}
+
+type ratingChartBar struct {
+	Start    time.Time
+	X        int
+	LabelX   int
+	Total    int
+	Segments []ratingChartSegment
+}
+
+type heatmapCell struct {
+	Date   time.Time
+	X      int
+	Y      int
+	Rating int32
+	Count  int
+}
+
+type journalStreaks struct {
+	Current       int
+	Longest       int
+	LongestStart  time.Time
+	LongestEnd    time.Time
+	DaysJournaled int
+}
+
+type journalStatsData struct {
+	pageData
+
+	Period  string
+	Periods []string
+
+	Bars          []ratingChartBar
+	ChartWidth    int
+	ChartHeight   int
+	ChartBarWidth int
+	ChartBaseline int
+
+	Cells         []heatmapCell
+	HeatmapWidth  int
+	HeatmapHeight int
+	HeatmapCell   int
+
+	Streaks journalStreaks
+}
+
+// getJournalDays groups the ratings of journal entries by the day they were written on in `location`
+func getJournalDays(ratings []models.GetJournalEntryRatingsRow, location *time.Location) map[time.Time][]int32 {
+	days := map[time.Time][]int32{}
+	for _, rating := range ratings {
+		date := rating.Date.In(location)
+		day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+		days[day] = append(days[day], rating.Rating)
+	}
+
+	return days
+}
+
+func getAverageRating(ratings []int32) int32 {
+	if len(ratings) == 0 {
+		return 0
+	}
+
+	sum := 0
+	for _, rating := range ratings {
+		sum += int(rating)
+	}
+
+	return int32(math.Round(float64(sum) / float64(len(ratings))))
+}
+
+// getJournalStreaks returns the current and longest streaks of consecutive days with journal entries.
+// A streak that ended yesterday is still current, since there is time left to write today's entry.
+func getJournalStreaks(days map[time.Time][]int32, today time.Time) journalStreaks {
+	streaks := journalStreaks{
+		DaysJournaled: len(days),
+	}
+
+	day := today
+	if _, ok := days[day]; !ok {
+		day = day.AddDate(0, 0, -1)
+	}
+
+	for {
+		if _, ok := days[day]; !ok {
+			break
+		}
+
+		streaks.Current++
+		day = day.AddDate(0, 0, -1)
+	}
+
+	sortedDays := []time.Time{}
+	for day := range days {
+		sortedDays = append(sortedDays, day)
+	}
+	slices.SortFunc(sortedDays, func(a, b time.Time) int {
+		return a.Compare(b)
+	})
+
+	length := 0
+	for i, day := range sortedDays {
+		if i > 0 && sortedDays[i-1].AddDate(0, 0, 1).Equal(day) {
+			length++
+		} else {
+			length = 1
+		}
+
+		if length > streaks.Longest {
+			streaks.Longest = length
+			streaks.LongestStart = day.AddDate(0, 0, -(length - 1))
+			streaks.LongestEnd = day
+		}
+	}
+
+	return streaks
+}
+
+// getPeriodStart returns the first day of the week (starting on Monday) or month that `day` is in
+func getPeriodStart(day time.Time, period string) time.Time {
+	if period == journalStatsPeriodMonth {
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// getRatingChartBars counts the ratings for each of the last periods up to and including `today` and
+// lays them out as stacked bars, with bad days at the bottom and great days at the top
+func getRatingChartBars(days map[time.Time][]int32, today time.Time, period string) []ratingChartBar {
+	bars := make([]ratingChartBar, ratingChartPeriods)
+	counts := make([]map[int32]int, ratingChartPeriods)
+
+	currentPeriodStart := getPeriodStart(today, period)
+	for i := range bars {
+		offset := ratingChartPeriods - 1 - i
+
+		start := currentPeriodStart.AddDate(0, 0, -7*offset)
+		if period == journalStatsPeriodMonth {
+			start = currentPeriodStart.AddDate(0, -offset, 0)
+		}
+
+		bars[i] = ratingChartBar{
+			Start:  start,
+			X:      i * (ratingChartBarWidth + ratingChartBarGap),
+			LabelX: i*(ratingChartBarWidth+ratingChartBarGap) + ratingChartBarWidth/2,
+		}
+		counts[i] = map[int32]int{}
+	}
+
+	for day, ratings := range days {
+		i := slices.IndexFunc(bars, func(bar ratingChartBar) bool {
+			return bar.Start.Equal(getPeriodStart(day, period))
+		})
+		if i == -1 {
+			continue
+		}
+
+		for _, rating := range ratings {
+			counts[i][rating]++
+			bars[i].Total++
+		}
+	}
+
+	maxTotal := 0
+	for _, bar := range bars {
+		maxTotal = max(maxTotal, bar.Total)
+	}
+
+	for i := range bars {
+		if bars[i].Total == 0 {
+			continue
+		}
+
+		y := ratingChartBarHeight
+		for _, rating := range []int32{1, 2, 3} {
+			count := counts[i][rating]
+			if count == 0 {
+				continue
+			}
+
+			height := count * ratingChartBarHeight / maxTotal
+			y -= height
+
+			bars[i].Segments = append(bars[i].Segments, ratingChartSegment{
+				Rating: rating,
+				Count:  count,
+				Y:      y,
+				Height: height,
+			})
+		}
+	}
+
+	return bars
+}
+
+// getHeatmapCells lays out the days of the last year as a calendar with one column per week
+// and one row per weekday, colored by the average rating of the day
+func getHeatmapCells(days map[time.Time][]int32, today time.Time) []heatmapCell {
+	cells := []heatmapCell{}
+
+	start := getPeriodStart(today, journalStatsPeriodWeek).AddDate(0, 0, -7*(heatmapWeeks-1))
+	for day, i := start, 0; !day.After(today); day, i = day.AddDate(0, 0, 1), i+1 {
+		ratings := days[day]
+
+		cells = append(cells, heatmapCell{
+			Date:   day,
+			X:      (i / 7) * (heatmapCellSize + heatmapCellGap),
+			Y:      (i % 7) * (heatmapCellSize + heatmapCellGap),
+			Rating: getAverageRating(ratings),
+			Count:  len(ratings),
+		})
+	}
+
+	return cells
+}
+
+func (b *Controller) HandleJournalStats(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	period := r.URL.Query().Get("period")
+	if period == "" {
+		period = journalStatsPeriodWeek
+	} else if !slices.Contains(journalStatsPeriods, period) {
+		log.Println(errInvalidQueryParam)
+
+		http.Error(w, errInvalidQueryParam.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	ratings, err := b.persister.GetJournalEntryRatings(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	location, err := b.getLocation(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	today := getToday(location)
+	days := getJournalDays(ratings, location)
+
+	if err := b.tpl.ExecuteTemplate(w, "journal_stats.html", journalStatsData{
+		pageData: pageData{
+			userData: userData,
+
+			Page:       userData.Locale.Get("Statistics"),
+			PrivacyURL: b.privacyURL,
+			ImprintURL: b.imprintURL,
+
+			BackURL: "/journal",
+		},
+
+		Period:  period,
+		Periods: journalStatsPeriods,
+
+		Bars:          getRatingChartBars(days, today, period),
+		ChartWidth:    ratingChartPeriods*(ratingChartBarWidth+ratingChartBarGap) - ratingChartBarGap,
+		ChartHeight:   ratingChartBarHeight + ratingChartLabelSize,
+		ChartBarWidth: ratingChartBarWidth,
+		ChartBaseline: ratingChartBarHeight,
+
+		Cells:         getHeatmapCells(days, today),
+		HeatmapWidth:  heatmapWeeks*(heatmapCellSize+heatmapCellGap) - heatmapCellGap,
+		HeatmapHeight: 7*(heatmapCellSize+heatmapCellGap) - heatmapCellGap,
+		HeatmapCell:   heatmapCellSize,
+
+		Streaks: getJournalStreaks(days, today),
+	}); err != nil {
+		log.Println(errCouldNotRenderTemplate, err)
+
+		http.Error(w, errCouldNotRenderTemplate.Error(), http.StatusInternalServerError)
+
+		return
+	}
+}
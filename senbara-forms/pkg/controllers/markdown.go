@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/wikilinks"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+const (
+	// markdownSanitizerPriority makes the sanitizer run after all other AST transformers, including the ones of extensions
+	markdownSanitizerPriority = 10000
+
+	externalLinkRel = "noopener noreferrer"
+)
+
+var (
+	// allowedMarkdownNodeKinds are the only nodes that get rendered; all others, including raw HTML, are dropped
+	allowedMarkdownNodeKinds = []ast.NodeKind{
+		ast.KindDocument,
+		ast.KindTextBlock,
+		ast.KindParagraph,
+		ast.KindHeading,
+		ast.KindThematicBreak,
+		ast.KindCodeBlock,
+		ast.KindFencedCodeBlock,
+		ast.KindBlockquote,
+		ast.KindList,
+		ast.KindListItem,
+
+		ast.KindText,
+		ast.KindString,
+		ast.KindCodeSpan,
+		ast.KindEmphasis,
+		ast.KindLink,
+		ast.KindImage,
+		ast.KindAutoLink,
+
+		east.KindTable,
+		east.KindTableHeader,
+		east.KindTableRow,
+		east.KindTableCell,
+		east.KindStrikethrough,
+		east.KindTaskCheckBox,
+	}
+
+	allowedMarkdownLinkSchemes  = []string{"http", "https", "mailto"}
+	allowedMarkdownImageSchemes = []string{"http", "https"}
+)
+
+// newMarkdown creates the Markdown renderer used for journal entries
+func newMarkdown() goldmark.Markdown {
+	return goldmark.New(
+		goldmark.WithExtensions(extension.GFM, wikilinks.Extension),
+		goldmark.WithParserOptions(
+			parser.WithASTTransformers(
+				util.Prioritized(&attachmentURLTransformer{}, 100),
+				util.Prioritized(&markdownSanitizer{}, markdownSanitizerPriority),
+			),
+		),
+	)
+}
+
+// markdownSanitizer removes everything from a Markdown document that could carry dangerous HTML,
+// since journal entries can be imported from untrusted files. Instead of cleaning up the rendered
+// HTML, it works on the AST, where only allowlisted nodes with allowlisted URLs are kept.
+type markdownSanitizer struct{}
+
+func (s *markdownSanitizer) Transform(node *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+
+	// Nodes can't be replaced while walking the tree, so this maps them to their replacements,
+	// or to `nil` if they should be removed
+	replacements := map[ast.Node]ast.Node{}
+
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		if !slices.Contains(allowedMarkdownNodeKinds, n.Kind()) {
+			replacements[n] = nil
+
+			return ast.WalkSkipChildren, nil
+		}
+
+		// Only attributes set by the sanitizer are rendered
+		n.RemoveAttributes()
+
+		switch n := n.(type) {
+		// URLs are checked in the form that the renderer writes them in, since
+		// it resolves references like `&colon;` in link and image destinations
+		case *ast.Link:
+			destination := util.URLEscape(n.Destination, true)
+			if !isAllowedMarkdownURL(destination, allowedMarkdownLinkSchemes) {
+				n.Destination = nil
+			} else if isExternalURL(destination) {
+				n.SetAttributeString("rel", []byte(externalLinkRel))
+			}
+
+		case *ast.Image:
+			if !isAllowedMarkdownURL(util.URLEscape(n.Destination, true), allowedMarkdownImageSchemes) {
+				n.Destination = nil
+			}
+
+		case *ast.AutoLink:
+			// Unlike for links, goldmark doesn't check the URLs of autolinks like `<javascript:alert(1)>`
+			destination := util.URLEscape(n.URL(source), false)
+			if n.AutoLinkType == ast.AutoLinkURL && !isAllowedMarkdownURL(destination, allowedMarkdownLinkSchemes) {
+				replacements[n] = ast.NewString(n.Label(source))
+
+				return ast.WalkSkipChildren, nil
+			}
+
+			if isExternalURL(destination) {
+				n.SetAttributeString("rel", []byte(externalLinkRel))
+			}
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	for n, replacement := range replacements {
+		parent := n.Parent()
+		if parent == nil {
+			continue
+		}
+
+		if replacement == nil {
+			parent.RemoveChild(parent, n)
+		} else {
+			parent.ReplaceChild(parent, n, replacement)
+		}
+	}
+}
+
+// isAllowedMarkdownURL reports whether `destination` is relative or uses one of `schemes`
+func isAllowedMarkdownURL(destination []byte, schemes []string) bool {
+	u, err := url.Parse(string(destination))
+	if err != nil {
+		return false
+	}
+
+	return u.Scheme == "" || slices.Contains(schemes, strings.ToLower(u.Scheme))
+}
+
+func isExternalURL(destination []byte) bool {
+	u, err := url.Parse(string(destination))
+	if err != nil {
+		return false
+	}
+
+	return u.Host != ""
+}
@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"bytes"
+	"html"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var (
+	markdownTestTagPattern          = regexp.MustCompile(`(?i)<\s*(script|iframe|object|embed|style|svg|math)\b`)
+	markdownTestEventHandlerPattern = regexp.MustCompile(`(?i)<[^>]*\son[a-z]+\s*=`)
+	markdownTestURLAttributePattern = regexp.MustCompile(`(?i)\s(href|src)="([^"]*)"`)
+	markdownTestLinkPattern         = regexp.MustCompile(`<a\s[^>]*>`)
+)
+
+func renderTestMarkdown(t *testing.T, input string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := newMarkdown().Convert([]byte(input), &buf); err != nil {
+		t.Fatalf("could not render Markdown: %v", err)
+	}
+
+	return buf.String()
+}
+
+// assertNoExecutableMarkdown fails if the rendered HTML contains any tags, event handlers or URLs that could run code
+func assertNoExecutableMarkdown(t *testing.T, output string) {
+	t.Helper()
+
+	if markdownTestTagPattern.MatchString(output) {
+		t.Errorf("rendered HTML contains a dangerous tag: %q", output)
+	}
+
+	if markdownTestEventHandlerPattern.MatchString(output) {
+		t.Errorf("rendered HTML contains an event handler: %q", output)
+	}
+
+	for _, match := range markdownTestURLAttributePattern.FindAllStringSubmatch(output, -1) {
+		// Browsers resolve character references and ignore surrounding whitespace in URL attributes
+		u, err := url.Parse(strings.TrimSpace(html.UnescapeString(match[2])))
+		if err != nil {
+			t.Errorf("rendered HTML contains an invalid URL in %v attribute: %q", match[1], output)
+
+			continue
+		}
+
+		if u.Scheme != "" && !slices.Contains(allowedMarkdownLinkSchemes, strings.ToLower(u.Scheme)) {
+			t.Errorf("rendered HTML contains a URL with scheme %q in %v attribute: %q", u.Scheme, match[1], output)
+		}
+	}
+}
+
+func TestMarkdownSanitizer(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"raw script block", "<script>alert(1)</script>"},
+		{"inline script", "Hello <script>alert(1)</script> world"},
+		{"image with event handler", `<img src=x onerror=alert(1)>`},
+		{"inline image with event handler", `Hello <img src=x onerror="alert(1)"> world`},
+		{"link with JavaScript scheme", "[x](javascript:alert(1))"},
+		{"link with uppercase JavaScript scheme", "[x](JaVaScRiPt:alert(1))"},
+		{"link with entity-obfuscated JavaScript scheme", "[x](javascript&#58;alert(1))"},
+		{"link with named entity-obfuscated JavaScript scheme", "[x](javascript&colon;alert(1))"},
+		{"link with hex entity-obfuscated JavaScript scheme", "[x](&#x6A;avascript:alert(1))"},
+		{"reference link with JavaScript scheme", "[x][ref]\n\n[ref]: javascript:alert(1)"},
+		{"autolink with JavaScript scheme", "<javascript:alert(1)>"},
+		{"autolink with uppercase JavaScript scheme", "<JaVaScRiPt:alert(1)>"},
+		{"image with data URL", "![](data:text/html,<script>alert(1)</script>)"},
+		{"image with base64 data URL", "![](data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==)"},
+		{"image with JavaScript scheme", "![x](javascript:alert(1))"},
+		{"link with data URL", "[x](data:text/html,<script>alert(1)</script>)"},
+		{"iframe", `<iframe src="https://example.com"></iframe>`},
+		{"script in table cell", "| a |\n| - |\n| <script>alert(1)</script> |"},
+		{"link in blockquote with JavaScript scheme", "> [x](javascript:alert(1))"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertNoExecutableMarkdown(t, renderTestMarkdown(t, tt.input))
+		})
+	}
+}
+
+func TestMarkdownSanitizerKeepsSafeMarkdown(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		output string
+	}{
+		{"emphasis", "*Hello*", "<em>Hello</em>"},
+		{"relative link", "[x](/contacts/view?id=1)", `href="/contacts/view?id=1"`},
+		{"mailto link", "[x](mailto:jane@example.com)", `href="mailto:jane@example.com"`},
+		{"image", "![x](https://example.com/image.png)", `src="https://example.com/image.png"`},
+		{"code block", "```\n<script>alert(1)</script>\n```", "&lt;script&gt;alert(1)&lt;/script&gt;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := renderTestMarkdown(t, tt.input)
+			if !strings.Contains(output, tt.output) {
+				t.Errorf("rendered HTML %q does not contain %q", output, tt.output)
+			}
+
+			assertNoExecutableMarkdown(t, output)
+		})
+	}
+}
+
+func TestMarkdownSanitizerExternalLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		external bool
+	}{
+		{"external link", "[x](https://example.com)", true},
+		{"external link with uppercase scheme", "[x](HTTPS://example.com)", true},
+		{"external autolink", "<https://example.com>", true},
+		{"external bare URL", "https://example.com", true},
+		{"relative link", "[x](/journal)", false},
+		{"mailto link", "[x](mailto:jane@example.com)", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := renderTestMarkdown(t, tt.input)
+
+			links := markdownTestLinkPattern.FindAllString(output, -1)
+			if len(links) != 1 {
+				t.Fatalf("expected exactly one link in rendered HTML, got %q", output)
+			}
+
+			hasRel := strings.Contains(links[0], `rel="`+externalLinkRel+`"`)
+			if tt.external && !hasRel {
+				t.Errorf("external link %q is missing rel=%q", links[0], externalLinkRel)
+			} else if !tt.external && hasRel {
+				t.Errorf("internal link %q has unexpected rel=%q", links[0], externalLinkRel)
+			}
+		})
+	}
+}
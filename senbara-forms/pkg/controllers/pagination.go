@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+const (
+	pageSize = 25
+
+	orderAscending  = "asc"
+	orderDescending = "desc"
+)
+
+type sortOption struct {
+	Sort       string
+	Descending bool
+	Active     bool
+}
+
+type queryFilter struct {
+	Key   string
+	Value string
+}
+
+type pagination struct {
+	Sort       string
+	Descending bool
+	After      string
+	Before     string
+	Previous   string
+	Next       string
+	Sorts      []sortOption
+	Filters    []queryFilter
+}
+
+// backwards reports whether the page ends before the cursor instead of starting after it, in which case
+// the rows have to be fetched in the reverse order
+func (p pagination) backwards() bool {
+	return p.Before != ""
+}
+
+// encodeCursor encodes the position of the first or last row of a page for the `before` and `after` query parameters
+func encodeCursor(cursor any) (string, error) {
+	b, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// getPagination parses the `sort`, `order`, `after` and `before` query parameters, decoding the cursor in `after`
+// or `before` into `cursor`. The first of the `sorts` is the default, and each of them carries the order that it defaults to.
+func getPagination(r *http.Request, sorts []sortOption, cursor any) (pagination, error) {
+	query := r.URL.Query()
+
+	p := pagination{
+		Sort:       sorts[0].Sort,
+		Descending: sorts[0].Descending,
+	}
+	if rsort := strings.TrimSpace(query.Get("sort")); rsort != "" {
+		found := false
+		for _, s := range sorts {
+			if s.Sort == rsort {
+				p.Sort = s.Sort
+				p.Descending = s.Descending
+
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return pagination{}, errInvalidQueryParam
+		}
+	}
+
+	switch query.Get("order") {
+	case "":
+		break
+
+	case orderAscending:
+		p.Descending = false
+
+	case orderDescending:
+		p.Descending = true
+
+	default:
+		return pagination{}, errInvalidQueryParam
+	}
+
+	p.After = strings.TrimSpace(query.Get("after"))
+	p.Before = strings.TrimSpace(query.Get("before"))
+	if p.After != "" && p.Before != "" {
+		return pagination{}, errInvalidQueryParam
+	}
+
+	if rcursor := p.After + p.Before; rcursor != "" {
+		b, err := base64.RawURLEncoding.DecodeString(rcursor)
+		if err != nil {
+			return pagination{}, errors.Join(errInvalidQueryParam, err)
+		}
+
+		if err := json.Unmarshal(b, cursor); err != nil {
+			return pagination{}, errors.Join(errInvalidQueryParam, err)
+		}
+	}
+
+	// Selecting the active sort again toggles its order, all others start with their default order
+	for _, s := range sorts {
+		if s.Sort == p.Sort {
+			s.Active = true
+			s.Descending = !p.Descending
+		}
+
+		p.Sorts = append(p.Sorts, s)
+	}
+
+	return p, nil
+}
+
+// paginate turns the rows fetched for a page, which include one more row than shown to find out whether there
+// are more pages, into the rows of the page, and sets the cursors for the links to the previous and next pages.
+// Rows fetched backwards are put back into the order of the page.
+func paginate[T any](p *pagination, rows []T, getCursor func(row T) any) ([]T, error) {
+	more := len(rows) > pageSize
+	if more {
+		rows = rows[:pageSize]
+	}
+
+	if p.backwards() {
+		slices.Reverse(rows)
+	}
+
+	if len(rows) == 0 {
+		return rows, nil
+	}
+
+	if (p.backwards() && more) || p.After != "" {
+		previous, err := encodeCursor(getCursor(rows[0]))
+		if err != nil {
+			return nil, err
+		}
+
+		p.Previous = previous
+	}
+
+	if more || p.backwards() {
+		next, err := encodeCursor(getCursor(rows[len(rows)-1]))
+		if err != nil {
+			return nil, err
+		}
+
+		p.Next = next
+	}
+
+	return rows, nil
+}
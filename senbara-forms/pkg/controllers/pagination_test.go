@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/persisters"
+)
+
+func TestPaginationCursorRoundTrip(t *testing.T) {
+	want := persisters.ContactsCursor{
+		ID:        42,
+		FirstName: "Jane, \"JJ\"",
+		LastName:  "Doe & Söhne",
+		UpdatedAt: time.Date(2026, 10, 18, 12, 0, 0, 123456000, time.UTC),
+	}
+
+	next, err := encodeCursor(want)
+	if err != nil {
+		t.Fatalf("could not encode cursor: %v", err)
+	}
+
+	var got persisters.ContactsCursor
+	p, err := getPagination(
+		httptest.NewRequest("GET", "/contacts?"+url.Values{"after": {next}}.Encode(), nil),
+		[]sortOption{{Sort: persisters.ContactsSortName}},
+		&got,
+	)
+	if err != nil {
+		t.Fatalf("could not get pagination: %v", err)
+	}
+
+	if got != want {
+		t.Errorf("getPagination() cursor = %#v, want %#v", got, want)
+	}
+
+	if p.After != next {
+		t.Errorf("getPagination() After = %q, want %q", p.After, next)
+	}
+}
+
+func TestPaginationInvalidCursor(t *testing.T) {
+	for _, after := range []string{"42", "not base64!", "bm90IGpzb24"} {
+		t.Run(after, func(t *testing.T) {
+			var cursor persisters.ContactsCursor
+			if _, err := getPagination(
+				httptest.NewRequest("GET", "/contacts?"+url.Values{"after": {after}}.Encode(), nil),
+				[]sortOption{{Sort: persisters.ContactsSortName}},
+				&cursor,
+			); !errors.Is(err, errInvalidQueryParam) {
+				t.Errorf("getPagination() error = %v, want %v", err, errInvalidQueryParam)
+			}
+		})
+	}
+}
+
+func TestPaginationConflictingCursors(t *testing.T) {
+	cursor, err := encodeCursor(persisters.ContactsCursor{ID: 1})
+	if err != nil {
+		t.Fatalf("could not encode cursor: %v", err)
+	}
+
+	var got persisters.ContactsCursor
+	if _, err := getPagination(
+		httptest.NewRequest("GET", "/contacts?"+url.Values{"after": {cursor}, "before": {cursor}}.Encode(), nil),
+		[]sortOption{{Sort: persisters.ContactsSortName}},
+		&got,
+	); !errors.Is(err, errInvalidQueryParam) {
+		t.Errorf("getPagination() error = %v, want %v", err, errInvalidQueryParam)
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	// getRows returns rows with consecutive IDs, which are also the cursors
+	getRows := func(first, count int) []int {
+		rows := []int{}
+		for i := range count {
+			rows = append(rows, first+i)
+		}
+
+		return rows
+	}
+
+	// Pages that end before a cursor are fetched in the reverse order
+	reverse := func(rows []int) []int {
+		slices.Reverse(rows)
+
+		return rows
+	}
+
+	tests := []struct {
+		name      string
+		p         pagination
+		rows      []int
+		wantFirst int
+		wantLast  int
+		previous  bool
+		next      bool
+	}{
+		{"first page", pagination{}, getRows(1, pageSize+1), 1, pageSize, false, true},
+		{"only page", pagination{}, getRows(1, pageSize), 1, pageSize, false, false},
+		{"page after a cursor", pagination{After: "a"}, getRows(26, pageSize+1), 26, 25 + pageSize, true, true},
+		{"last page", pagination{After: "a"}, getRows(26, 3), 26, 28, true, false},
+		{"page before a cursor", pagination{Before: "b"}, reverse(getRows(26, pageSize+1)), 27, 26 + pageSize, true, true},
+		{"page before a cursor at the beginning", pagination{Before: "b"}, reverse(getRows(1, 3)), 1, 3, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := paginate(&tt.p, tt.rows, func(row int) any {
+				return row
+			})
+			if err != nil {
+				t.Fatalf("could not paginate: %v", err)
+			}
+
+			if first, last := rows[0], rows[len(rows)-1]; first != tt.wantFirst || last != tt.wantLast {
+				t.Errorf("paginate() rows = %v..%v, want %v..%v", first, last, tt.wantFirst, tt.wantLast)
+			}
+
+			wantPrevious, wantNext := "", ""
+			if tt.previous {
+				wantPrevious, _ = encodeCursor(tt.wantFirst)
+			}
+
+			if tt.next {
+				wantNext, _ = encodeCursor(tt.wantLast)
+			}
+
+			if tt.p.Previous != wantPrevious || tt.p.Next != wantNext {
+				t.Errorf("paginate() previous, next = %q, %q, want %q, %q", tt.p.Previous, tt.p.Next, wantPrevious, wantNext)
+			}
+		})
+	}
+}
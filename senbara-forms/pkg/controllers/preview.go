@@ -0,0 +1,265 @@
+package controllers
+
+import (
+	"bytes"
+	"html/template"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/persisters"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var (
+	// previewBlockKinds are the blocks whose inline content is shown in previews
+	previewBlockKinds = []ast.NodeKind{
+		ast.KindParagraph,
+		ast.KindTextBlock,
+		ast.KindHeading,
+		east.KindTableCell,
+	}
+)
+
+// getTruncationIndex returns the number of runes of `runes` to keep so that at most `length` are left.
+// It cuts at the last word boundary if there is one, and otherwise never separates a character from the
+// combining marks that follow it.
+func getTruncationIndex(runes []rune, length int) int {
+	if len(runes) <= length {
+		return len(runes)
+	}
+
+	// A space right after the cut is a word boundary too
+	for i := length; i > 0; i-- {
+		if unicode.IsSpace(runes[i]) {
+			return i
+		}
+	}
+
+	i := length
+	for i > 0 && unicode.Is(unicode.M, runes[i]) {
+		i--
+	}
+
+	return i
+}
+
+// truncateText shortens `text` to at most `length` characters and adds an ellipsis if it was shortened
+func truncateText(text string, length int) string {
+	runes := []rune(text)
+
+	i := getTruncationIndex(runes, length)
+	if i == len(runes) {
+		return text
+	}
+
+	return strings.TrimRightFunc(string(runes[:i]), unicode.IsSpace) + "…"
+}
+
+// getPreviewLeafText returns the text that a node of a flattened preview is shown with
+func getPreviewLeafText(n ast.Node, source []byte) []rune {
+	switch n := n.(type) {
+	case *ast.String:
+		return []rune(string(n.Value))
+
+	case *ast.AutoLink:
+		return []rune(string(n.Label(source)))
+
+	case *ast.CodeSpan:
+		var code []byte
+		for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+			if t, ok := c.(*ast.Text); ok {
+				code = append(code, t.Segment.Value(source)...)
+			}
+		}
+
+		return []rune(strings.ReplaceAll(string(code), "\n", " "))
+	}
+
+	return nil
+}
+
+// getPreviewLeaves returns the nodes of a flattened preview that carry text, in the order they are shown in
+func getPreviewLeaves(preview ast.Node) []ast.Node {
+	leaves := []ast.Node{}
+	_ = ast.Walk(preview, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n.(type) {
+		case *ast.String, *ast.AutoLink, *ast.CodeSpan:
+			leaves = append(leaves, n)
+
+			return ast.WalkSkipChildren, nil
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return leaves
+}
+
+// newPreviewString creates a node that shows `text` as-is, with only HTML being escaped
+func newPreviewString(text string) *ast.String {
+	s := ast.NewString([]byte(text))
+	s.SetRaw(true)
+
+	return s
+}
+
+// flattenMarkdownPreview moves the inline content of all blocks in `doc` into a single line, since previews
+// are shown inline. Images and task list checkboxes are dropped, and all text is resolved so that it can be
+// shown as plain text as well as with formatting.
+func flattenMarkdownPreview(doc ast.Node, source []byte) *ast.TextBlock {
+	blocks := []ast.Node{}
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		if slices.Contains(previewBlockKinds, n.Kind()) || n.Kind() == ast.KindCodeBlock || n.Kind() == ast.KindFencedCodeBlock {
+			blocks = append(blocks, n)
+
+			return ast.WalkSkipChildren, nil
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	preview := ast.NewTextBlock()
+	for i, block := range blocks {
+		if i > 0 {
+			preview.AppendChild(preview, newPreviewString(" "))
+		}
+
+		if !slices.Contains(previewBlockKinds, block.Kind()) {
+			var code bytes.Buffer
+			for j := 0; j < block.Lines().Len(); j++ {
+				line := block.Lines().At(j)
+
+				code.Write(line.Value(source))
+			}
+
+			preview.AppendChild(preview, newPreviewString(strings.TrimSpace(strings.ReplaceAll(code.String(), "\n", " "))))
+
+			continue
+		}
+
+		for c := block.FirstChild(); c != nil; {
+			next := c.NextSibling()
+
+			preview.AppendChild(preview, c)
+
+			c = next
+		}
+	}
+
+	// Nodes can't be replaced while walking the tree, so this maps them to their replacements,
+	// or to `nil` if they should be removed
+	replacements := map[ast.Node]ast.Node{}
+	_ = ast.Walk(preview, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := n.(type) {
+		case *ast.Image, *east.TaskCheckBox:
+			replacements[n] = nil
+
+			return ast.WalkSkipChildren, nil
+
+		case *ast.CodeSpan, *ast.AutoLink:
+			return ast.WalkSkipChildren, nil
+
+		case *ast.Text:
+			value := n.Segment.Value(source)
+			if !n.IsRaw() {
+				value = util.UnescapePunctuations(util.ResolveEntityNames(util.ResolveNumericReferences(value)))
+			}
+
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				value = append(value, ' ')
+			}
+
+			replacements[n] = newPreviewString(string(value))
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	for n, replacement := range replacements {
+		parent := n.Parent()
+		if replacement == nil {
+			parent.RemoveChild(parent, n)
+		} else {
+			parent.ReplaceChild(parent, n, replacement)
+		}
+	}
+
+	return preview
+}
+
+// renderMarkdownPreview renders a preview of at most `length` characters of the Markdown in `body`.
+// With `persisters.PreviewFormatHTML`, inline formatting and links are kept, and the preview is cut
+// in the same place as the plain text one.
+func renderMarkdownPreview(md goldmark.Markdown, body string, length int, format string) (template.HTML, error) {
+	source := []byte(body)
+
+	preview := flattenMarkdownPreview(md.Parser().Parse(text.NewReader(source)), source)
+	leaves := getPreviewLeaves(preview)
+
+	plain := []rune{}
+	for _, leaf := range leaves {
+		plain = append(plain, getPreviewLeafText(leaf, source)...)
+	}
+
+	if format != persisters.PreviewFormatHTML {
+		return template.HTML(template.HTMLEscapeString(truncateText(string(plain), length))), nil
+	}
+
+	cut := getTruncationIndex(plain, length)
+	if cut < len(plain) {
+		start := 0
+		for _, leaf := range leaves {
+			leafText := getPreviewLeafText(leaf, source)
+			end := start + len(leafText)
+
+			if cut > end {
+				start = end
+
+				continue
+			}
+
+			// The leaf that the preview is cut in is replaced with its truncated text, and everything after it is dropped
+			truncated := newPreviewString(strings.TrimRightFunc(string(leafText[:cut-start]), unicode.IsSpace) + "…")
+			leaf.Parent().ReplaceChild(leaf.Parent(), leaf, truncated)
+
+			for n := ast.Node(truncated); n != preview; n = n.Parent() {
+				for s := n.NextSibling(); s != nil; {
+					next := s.NextSibling()
+
+					n.Parent().RemoveChild(n.Parent(), s)
+
+					s = next
+				}
+			}
+
+			break
+		}
+	}
+
+	doc := ast.NewDocument()
+	doc.AppendChild(doc, preview)
+
+	var buf bytes.Buffer
+	if err := md.Renderer().Render(&buf, source, doc); err != nil {
+		return "", err
+	}
+
+	return template.HTML(strings.TrimSpace(buf.String())), nil
+}
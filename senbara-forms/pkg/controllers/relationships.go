@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/persisters"
+)
+
+func (b *Controller) HandleCreateRelationship(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		log.Println(errCouldNotParseForm, err)
+
+		http.Error(w, errCouldNotParseForm.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	rcontactID := r.FormValue("contact_id")
+	if strings.TrimSpace(rcontactID) == "" {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	contactID, err := strconv.Atoi(rcontactID)
+	if err != nil {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	rrelatedContactID := r.FormValue("related_contact_id")
+	if strings.TrimSpace(rrelatedContactID) == "" {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	relatedContactID, err := strconv.Atoi(rrelatedContactID)
+	if err != nil || relatedContactID == contactID {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	kind := r.FormValue("kind")
+	if _, ok := persisters.RelationshipKindInverses[kind]; !ok {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	if _, err := b.persister.CreateRelationship(
+		r.Context(),
+
+		int32(relatedContactID),
+		kind,
+
+		int32(contactID),
+		userData.Email,
+	); err != nil {
+		if errors.Is(err, persisters.ErrContactDoesNotExist) {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		if errors.Is(err, persisters.ErrRelationshipAlreadyExists) {
+			log.Println(errCouldNotInsertIntoDB, err)
+
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+
+			return
+		}
+
+		log.Println(errCouldNotInsertIntoDB, err)
+
+		http.Error(w, errCouldNotInsertIntoDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/contacts/edit?id=%v", contactID), http.StatusFound)
+}
+
+func (b *Controller) HandleDeleteRelationship(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		log.Println(errCouldNotParseForm, err)
+
+		http.Error(w, errCouldNotParseForm.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	rid := r.FormValue("id")
+	if strings.TrimSpace(rid) == "" {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	id, err := strconv.Atoi(rid)
+	if err != nil {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	rcontactID := r.FormValue("contact_id")
+	if strings.TrimSpace(rcontactID) == "" {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	contactID, err := strconv.Atoi(rcontactID)
+	if err != nil {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	if err := b.persister.DeleteRelationship(
+		r.Context(),
+
+		int32(id),
+
+		int32(contactID),
+		userData.Email,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		log.Println(errCouldNotDeleteFromDB, err)
+
+		http.Error(w, errCouldNotDeleteFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/contacts/edit?id=%v", contactID), http.StatusFound)
+}
@@ -0,0 +1,432 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leonelquinteros/gotext"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/persisters"
+)
+
+const (
+	diffLineKindEqual  = "equal"
+	diffLineKindInsert = "insert"
+	diffLineKindDelete = "delete"
+)
+
+type diffLine struct {
+	Kind string
+	Text string
+}
+
+type revisionEntry struct {
+	ID        int32
+	CreatedAt time.Time
+	Diff      []diffLine
+}
+
+type historyData struct {
+	pageData
+	Entries []revisionEntry
+}
+
+// getLineDiff returns the lines that have to be deleted from and inserted into `before` to get `after`,
+// based on their longest common subsequence
+func getLineDiff(before, after string) []diffLine {
+	beforeLines := strings.Split(before, "\n")
+	afterLines := strings.Split(after, "\n")
+
+	lcs := make([][]int, len(beforeLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(afterLines)+1)
+	}
+
+	for i := len(beforeLines) - 1; i >= 0; i-- {
+		for j := len(afterLines) - 1; j >= 0; j-- {
+			if beforeLines[i] == afterLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	diff := []diffLine{}
+	i, j := 0, 0
+	for i < len(beforeLines) && j < len(afterLines) {
+		switch {
+		case beforeLines[i] == afterLines[j]:
+			diff = append(diff, diffLine{Kind: diffLineKindEqual, Text: beforeLines[i]})
+			i++
+			j++
+
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, diffLine{Kind: diffLineKindDelete, Text: beforeLines[i]})
+			i++
+
+		default:
+			diff = append(diff, diffLine{Kind: diffLineKindInsert, Text: afterLines[j]})
+			j++
+		}
+	}
+
+	for ; i < len(beforeLines); i++ {
+		diff = append(diff, diffLine{Kind: diffLineKindDelete, Text: beforeLines[i]})
+	}
+
+	for ; j < len(afterLines); j++ {
+		diff = append(diff, diffLine{Kind: diffLineKindInsert, Text: afterLines[j]})
+	}
+
+	return diff
+}
+
+func getRatingText(locale *gotext.Locale, rating int32) string {
+	switch rating {
+	case 3:
+		return locale.Get("Great")
+	case 2:
+		return locale.Get("OK")
+	case 1:
+		return locale.Get("Bad")
+	default:
+		return ""
+	}
+}
+
+func getContactFrequencyText(locale *gotext.Locale, contactFrequency string) string {
+	switch contactFrequency {
+	case persisters.ContactFrequencyWeekly:
+		return locale.Get("Weekly")
+	case persisters.ContactFrequencyMonthly:
+		return locale.Get("Monthly")
+	case persisters.ContactFrequencyQuarterly:
+		return locale.Get("Quarterly")
+	default:
+		return contactFrequency
+	}
+}
+
+// getJournalEntryText renders a journal entry as text so that two versions of it can be diffed line by line
+func getJournalEntryText(locale *gotext.Locale, journalEntry models.ExportedJournalEntry, location *time.Location) string {
+	var text strings.Builder
+
+	fmt.Fprintf(&text, "%v: %v\n", locale.Get("Title"), journalEntry.Title)
+	fmt.Fprintf(&text, "%v: %v\n", locale.Get("Date"), journalEntry.Date.In(location).Format("2006-01-02 15:04"))
+	fmt.Fprintf(&text, "%v: %v\n", locale.Get("Rating"), getRatingText(locale, journalEntry.Rating))
+	fmt.Fprintf(&text, "%v: %v\n", locale.Get("Tags"), strings.Join(journalEntry.Tags, ", "))
+	fmt.Fprintf(&text, "\n%v", journalEntry.Body)
+
+	return text.String()
+}
+
+// getContactText renders a contact as text so that two versions of it can be diffed line by line
+func getContactText(locale *gotext.Locale, contact models.ExportedContact) string {
+	var text strings.Builder
+
+	fmt.Fprintf(&text, "%v: %v\n", locale.Get("First name"), contact.FirstName)
+	fmt.Fprintf(&text, "%v: %v\n", locale.Get("Last name"), contact.LastName)
+	fmt.Fprintf(&text, "%v: %v\n", locale.Get("Nickname"), contact.Nickname)
+	fmt.Fprintf(&text, "%v: %v\n", locale.Get("Email"), contact.Email)
+	fmt.Fprintf(&text, "%v: %v\n", locale.Get("Pronouns"), contact.Pronouns)
+
+	birthday := ""
+	if contact.Birthday.Valid {
+		birthday = contact.Birthday.Time.Format("2006-01-02")
+	}
+	fmt.Fprintf(&text, "%v: %v\n", locale.Get("Birthday"), birthday)
+
+	fmt.Fprintf(&text, "%v: %v\n", locale.Get("Address"), contact.Address)
+	fmt.Fprintf(&text, "%v: %v\n", locale.Get("Keep in touch"), getContactFrequencyText(locale, contact.ContactFrequency))
+
+	customFieldNames := []string{}
+	for name := range contact.CustomFields {
+		customFieldNames = append(customFieldNames, name)
+	}
+	slices.Sort(customFieldNames)
+
+	for _, name := range customFieldNames {
+		fmt.Fprintf(&text, "%v: %v\n", name, contact.CustomFields[name])
+	}
+
+	fmt.Fprintf(&text, "\n%v", contact.Notes)
+
+	return text.String()
+}
+
+// getRevisionEntries diffs every revision against the version that replaced it, which is either
+// the next newer revision or the current version for the newest one
+func getRevisionEntries[T any](revisions []models.GetRevisionsRow, current T, location *time.Location, getText func(T) string) ([]revisionEntry, error) {
+	entries := []revisionEntry{}
+
+	after := getText(current)
+	for _, revision := range revisions {
+		var entity T
+		if err := json.Unmarshal(revision.Data, &entity); err != nil {
+			return nil, err
+		}
+
+		before := getText(entity)
+
+		entries = append(entries, revisionEntry{
+			ID:        revision.ID,
+			CreatedAt: revision.CreatedAt.In(location),
+			Diff:      getLineDiff(before, after),
+		})
+
+		after = before
+	}
+
+	return entries, nil
+}
+
+func (b *Controller) HandleJournalEntryHistory(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	redirected, journalKey, err := b.requireJournalKey(w, r, userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	rid := r.FormValue("id")
+	if strings.TrimSpace(rid) == "" {
+		log.Println(errInvalidQueryParam)
+
+		http.Error(w, errInvalidQueryParam.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	id, err := strconv.Atoi(rid)
+	if err != nil {
+		log.Println(errInvalidQueryParam)
+
+		http.Error(w, errInvalidQueryParam.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	journalEntry, revisions, err := b.persister.GetJournalEntryRevisions(r.Context(), journalKey, int32(id), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	location, err := b.getLocation(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	entries, err := getRevisionEntries(revisions, journalEntry, location, func(journalEntry models.ExportedJournalEntry) string {
+		return getJournalEntryText(userData.Locale, journalEntry, location)
+	})
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	entityURL := fmt.Sprintf("/journal/view?id=%v", journalEntry.ID)
+
+	if err := b.tpl.ExecuteTemplate(w, "history.html", historyData{
+		pageData: pageData{
+			userData: userData,
+
+			Page:       userData.Locale.Get("History of %v", journalEntry.Title),
+			PrivacyURL: b.privacyURL,
+			ImprintURL: b.imprintURL,
+
+			BackURL: entityURL,
+		},
+		Entries: entries,
+	}); err != nil {
+		log.Println(errCouldNotRenderTemplate, err)
+
+		http.Error(w, errCouldNotRenderTemplate.Error(), http.StatusInternalServerError)
+
+		return
+	}
+}
+
+func (b *Controller) HandleContactHistory(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	rid := r.FormValue("id")
+	if strings.TrimSpace(rid) == "" {
+		log.Println(errInvalidQueryParam)
+
+		http.Error(w, errInvalidQueryParam.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	id, err := strconv.Atoi(rid)
+	if err != nil {
+		log.Println(errInvalidQueryParam)
+
+		http.Error(w, errInvalidQueryParam.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	contact, revisions, err := b.persister.GetContactRevisions(r.Context(), int32(id), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	location, err := b.getLocation(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	entries, err := getRevisionEntries(revisions, contact, location, func(contact models.ExportedContact) string {
+		return getContactText(userData.Locale, contact)
+	})
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	entityURL := fmt.Sprintf("/contacts/view?id=%v", contact.ID)
+
+	if err := b.tpl.ExecuteTemplate(w, "history.html", historyData{
+		pageData: pageData{
+			userData: userData,
+
+			Page:       userData.Locale.Get("History of %v", contact.FirstName+" "+contact.LastName),
+			PrivacyURL: b.privacyURL,
+			ImprintURL: b.imprintURL,
+
+			BackURL: entityURL,
+		},
+		Entries: entries,
+	}); err != nil {
+		log.Println(errCouldNotRenderTemplate, err)
+
+		http.Error(w, errCouldNotRenderTemplate.Error(), http.StatusInternalServerError)
+
+		return
+	}
+}
+
+func (b *Controller) HandleRestoreRevision(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		log.Println(errCouldNotParseForm, err)
+
+		http.Error(w, errCouldNotParseForm.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	rid := r.FormValue("id")
+	if strings.TrimSpace(rid) == "" {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	id, err := strconv.Atoi(rid)
+	if err != nil {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	revision, err := b.persister.RestoreRevision(r.Context(), b.getJournalKey(r, userData.Email), int32(id), userData.Email)
+	if err != nil {
+		if errors.Is(err, persisters.ErrJournalLocked) {
+			http.Redirect(w, r, "/journal/encryption", http.StatusFound)
+
+			return
+		}
+
+		if errors.Is(err, persisters.ErrUnknownRevisionEntityName) {
+			log.Println(errCouldNotUpdateInDB, err)
+
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+
+			return
+		}
+
+		log.Println(errCouldNotUpdateInDB, err)
+
+		http.Error(w, errCouldNotUpdateInDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	switch revision.EntityName {
+	case persisters.RevisionEntityNameJournalEntry:
+		http.Redirect(w, r, fmt.Sprintf("/journal/view?id=%v", revision.EntityID), http.StatusFound)
+
+	default:
+		http.Redirect(w, r, fmt.Sprintf("/contacts/view?id=%v", revision.EntityID), http.StatusFound)
+	}
+}
@@ -6,12 +6,12 @@ import (
 	"errors"
 	"html/template"
 	"math"
+	"sync"
 
 	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
 	"github.com/pojntfx/senbara/senbara-forms/pkg/persisters"
 	"github.com/pojntfx/senbara/senbara-forms/pkg/templates"
-	"github.com/yuin/goldmark"
-	"github.com/yuin/goldmark/extension"
 	"golang.org/x/oauth2"
 )
 
@@ -29,8 +29,13 @@ var (
 	errCouldNotLocalize         = errors.New("could not localize")
 	errCouldNotWriteResponse    = errors.New("could not write response")
 	errCouldNotReadRequest      = errors.New("could not read request")
-	errUnknownEntityName        = errors.New("unknown entity name")
 	errCouldNotStartTransaction = errors.New("could not start transaction")
+	errCouldNotProcessPhoto     = errors.New("could not process photo")
+	errUnsupportedPhotoType     = errors.New("unsupported photo type")
+	errPhotoTooLarge            = errors.New("photo too large")
+	errCouldNotUnlockJournal    = errors.New("could not unlock journal")
+	errInvalidJournalMarkdown   = errors.New("could not use invalid journal Markdown")
+	errInvalidBackup            = errors.New("could not use invalid backup")
 )
 
 const (
@@ -40,6 +45,10 @@ const (
 
 type indexData struct {
 	pageData
+	Dashboard models.Dashboard
+
+	UpcomingBirthdays     []upcomingBirthday
+	UpcomingBirthdaysDays int
 }
 
 type Controller struct {
@@ -55,6 +64,9 @@ type Controller struct {
 
 	config   *oauth2.Config
 	verifier *oidc.IDTokenVerifier
+
+	journalUnlockSessions     map[string]journalUnlockSession
+	journalUnlockSessionsLock sync.Mutex
 }
 
 func NewController(
@@ -76,22 +88,16 @@ func NewController(
 
 		privacyURL: privacyURL,
 		imprintURL: imprintURL,
+
+		journalUnlockSessions: map[string]journalUnlockSession{},
 	}
 }
 
 func (b *Controller) Init(ctx context.Context) error {
-	md := goldmark.New(
-		goldmark.WithExtensions(extension.GFM),
-	)
+	md := newMarkdown()
 
 	tpl, err := template.New("").Funcs(template.FuncMap{
-		"TruncateText": func(text string, length int) string {
-			if len(text) <= length {
-				return text
-			}
-
-			return text[:length] + "…"
-		},
+		"TruncateText": truncateText,
 		"RenderMarkdown": func(text string) template.HTML {
 			var buf bytes.Buffer
 			if err := md.Convert([]byte(text), &buf); err != nil {
@@ -100,9 +106,19 @@ func (b *Controller) Init(ctx context.Context) error {
 
 			return template.HTML(buf.String())
 		},
+		"RenderMarkdownPreview": func(text string, length int, format string) template.HTML {
+			preview, err := renderMarkdownPreview(md, text, length, format)
+			if err != nil {
+				panic(err)
+			}
+
+			return preview
+		},
 		"Abs": func(number float64) float64 {
 			return math.Abs(number)
 		},
+		"HighlightSearchHeadline": highlightSearchHeadline,
+		"IsInlineAttachment":      isInlineAttachment,
 	}).ParseFS(templates.FS, "*.html")
 	if err != nil {
 		return err
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/persisters"
+)
+
+const (
+	searchResultsLimit = 50
+)
+
+type searchData struct {
+	pageData
+	Query   string
+	Entries []models.SearchRow
+}
+
+// highlightSearchHeadline escapes a search headline and wraps the matched words in `<mark>` tags
+func highlightSearchHeadline(headline string) template.HTML {
+	return template.HTML(strings.NewReplacer(
+		persisters.SearchHighlightStart, "<mark>",
+		persisters.SearchHighlightStop, "</mark>",
+	).Replace(template.HTMLEscapeString(headline)))
+}
+
+func (b *Controller) HandleSearch(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+
+	var entries []models.SearchRow
+	if query != "" {
+		entries, err = b.persister.Search(r.Context(), b.getJournalKey(r, userData.Email), query, searchResultsLimit, userData.Email)
+		if err != nil {
+			log.Println(errCouldNotFetchFromDB, err)
+
+			http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	if err := b.tpl.ExecuteTemplate(w, "search.html", searchData{
+		pageData: pageData{
+			userData: userData,
+
+			Page:       userData.Locale.Get("Search"),
+			PrivacyURL: b.privacyURL,
+			ImprintURL: b.imprintURL,
+		},
+		Query:   query,
+		Entries: entries,
+	}); err != nil {
+		log.Println(errCouldNotRenderTemplate, err)
+
+		http.Error(w, errCouldNotRenderTemplate.Error(), http.StatusInternalServerError)
+
+		return
+	}
+}
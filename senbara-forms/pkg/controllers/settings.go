@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/persisters"
+)
+
+type settingsData struct {
+	pageData
+	TimeZone string
+
+	SearchLanguage  string
+	SearchLanguages []string
+
+	PreviewFormat  string
+	PreviewFormats []string
+
+	BackupRun *models.BackupRun
+}
+
+// getLocation returns the time zone that the user has configured in their settings.
+func (b *Controller) getLocation(ctx context.Context, namespace string) (*time.Location, error) {
+	settings, err := b.persister.GetSettings(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return time.LoadLocation(settings.TimeZone)
+}
+
+func (b *Controller) HandleSettings(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	settings, err := b.persister.GetSettings(r.Context(), userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	var backupRun *models.BackupRun
+	if run, err := b.persister.GetBackupRun(r.Context(), userData.Email); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Println(errCouldNotFetchFromDB, err)
+
+			http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+			return
+		}
+	} else {
+		location, err := time.LoadLocation(settings.TimeZone)
+		if err != nil {
+			log.Println(errCouldNotFetchFromDB, err)
+
+			http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		run.RanAt = run.RanAt.In(location)
+		run.LastSucceededAt.Time = run.LastSucceededAt.Time.In(location)
+
+		backupRun = &run
+	}
+
+	if err := b.tpl.ExecuteTemplate(w, "settings.html", settingsData{
+		pageData: pageData{
+			userData: userData,
+
+			Page:       userData.Locale.Get("Settings"),
+			PrivacyURL: b.privacyURL,
+			ImprintURL: b.imprintURL,
+		},
+		TimeZone: settings.TimeZone,
+
+		SearchLanguage:  settings.SearchLanguage,
+		SearchLanguages: persisters.SearchLanguages,
+
+		PreviewFormat:  settings.PreviewFormat,
+		PreviewFormats: persisters.PreviewFormats,
+
+		BackupRun: backupRun,
+	}); err != nil {
+		log.Println(errCouldNotRenderTemplate, err)
+
+		http.Error(w, errCouldNotRenderTemplate.Error(), http.StatusInternalServerError)
+
+		return
+	}
+}
+
+func (b *Controller) HandleUpdateSettings(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
+
+		http.Error(w, err.Error(), status)
+
+		return
+	} else if redirected {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		log.Println(errCouldNotParseForm, err)
+
+		http.Error(w, errCouldNotParseForm.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	timeZone := strings.TrimSpace(r.FormValue("time_zone"))
+	if timeZone == "" {
+		log.Println(errInvalidForm)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	// `time.LoadLocation` treats "Local" as the server's time zone, which isn't meaningful to users
+	if _, err := time.LoadLocation(timeZone); err != nil || timeZone == "Local" {
+		log.Println(errInvalidForm, err)
+
+		http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	searchLanguage := r.FormValue("search_language")
+	previewFormat := r.FormValue("preview_format")
+
+	if err := b.persister.UpdateSettings(r.Context(), timeZone, searchLanguage, previewFormat, userData.Email); err != nil {
+		if errors.Is(err, persisters.ErrInvalidSearchLanguage) || errors.Is(err, persisters.ErrInvalidPreviewFormat) {
+			log.Println(errInvalidForm, err)
+
+			http.Error(w, errInvalidForm.Error(), http.StatusUnprocessableEntity)
+
+			return
+		}
+
+		log.Println(errCouldNotUpdateInDB, err)
+
+		http.Error(w, errCouldNotUpdateInDB.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	http.Redirect(w, r, "/settings", http.StatusFound)
+}
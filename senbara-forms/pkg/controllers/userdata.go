@@ -1,20 +1,18 @@
 package controllers
 
 import (
-	"encoding/json"
 	"errors"
-	"io"
 	"log"
 	"net/http"
 
-	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/userdata"
 )
 
 const (
-	EntityNameExportedJournalEntry = "journalEntry"
-	EntityNameExportedContact      = "contact"
-	EntityNameExportedDebt         = "debt"
-	EntityNameExportedActivity     = "activity"
+	maxBackupUploadSize = 1024 * 1024 * 1024
+
+	// maxBackupUploadMemory is the part of an uploaded backup that is kept in memory, the rest is written to a temporary file
+	maxBackupUploadMemory = 32 * 1024 * 1024
 )
 
 func (b *Controller) HandleUserData(w http.ResponseWriter, r *http.Request) {
@@ -29,53 +27,21 @@ func (b *Controller) HandleUserData(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/jsonl")
-	w.Header().Set("Content-Disposition", `attachment; filename="senbara-forms-userdata.jsonl"`)
-
-	encoder := json.NewEncoder(w)
-
-	if err := b.persister.GetUserData(
-		r.Context(),
-
-		userData.Email,
-
-		func(journalEntry models.ExportedJournalEntry) error {
-			journalEntry.ExportedEntityIdentifier.EntityName = EntityNameExportedJournalEntry
-
-			if err := encoder.Encode(journalEntry); err != nil {
-				return errors.Join(errCouldNotWriteResponse, err)
-			}
-
-			return nil
-		},
-		func(contact models.ExportedContact) error {
-			contact.ExportedEntityIdentifier.EntityName = EntityNameExportedContact
-
-			if err := encoder.Encode(contact); err != nil {
-				return errors.Join(errCouldNotWriteResponse, err)
-			}
-
-			return nil
-		},
-		func(debt models.ExportedDebt) error {
-			debt.ExportedEntityIdentifier.EntityName = EntityNameExportedDebt
+	redirected, journalKey, err := b.requireJournalKey(w, r, userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
 
-			if err := encoder.Encode(debt); err != nil {
-				return errors.Join(errCouldNotWriteResponse, err)
-			}
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
 
-			return nil
-		},
-		func(activity models.ExportedActivity) error {
-			activity.ExportedEntityIdentifier.EntityName = EntityNameExportedActivity
+		return
+	} else if redirected {
+		return
+	}
 
-			if err := encoder.Encode(activity); err != nil {
-				return errors.Join(errCouldNotWriteResponse, err)
-			}
+	w.Header().Set("Content-Type", "application/jsonl")
+	w.Header().Set("Content-Disposition", `attachment; filename="senbara-forms-userdata.jsonl"`)
 
-			return nil
-		},
-	); err != nil {
+	if err := userdata.Export(r.Context(), b.persister, journalKey, userData.Email, w); err != nil {
 		log.Println(errCouldNotFetchFromDB, err)
 
 		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
@@ -96,43 +62,29 @@ func (b *Controller) HandleCreateUserData(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	file, _, err := r.FormFile("userData")
+	redirected, journalKey, err := b.requireJournalKey(w, r, userData.Email)
 	if err != nil {
-		log.Println(errCouldNotReadRequest, err)
+		log.Println(errCouldNotFetchFromDB, err)
 
-		http.Error(w, errCouldNotReadRequest.Error(), http.StatusInternalServerError)
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
 
+		return
+	} else if redirected {
 		return
 	}
-	defer file.Close()
-
-	decoder := json.NewDecoder(file)
-
-	createJournalEntry,
-		createContact,
-		createDebt,
-		createActivity,
-
-		commit,
-		rollback,
 
-		err := b.persister.CreateUserData(r.Context(), userData.Email)
+	file, _, err := r.FormFile("userData")
 	if err != nil {
-		log.Println(errCouldNotStartTransaction, err)
+		log.Println(errCouldNotReadRequest, err)
 
-		http.Error(w, errCouldNotStartTransaction.Error(), http.StatusInternalServerError)
+		http.Error(w, errCouldNotReadRequest.Error(), http.StatusInternalServerError)
 
 		return
 	}
-	defer rollback()
-
-	for {
-		var b json.RawMessage
-		if err := decoder.Decode(&b); err != nil {
-			if errors.Is(err, io.EOF) {
-				break
-			}
+	defer file.Close()
 
+	if err := userdata.Import(r.Context(), b.persister, journalKey, userData.Email, file); err != nil {
+		if errors.Is(err, userdata.ErrCouldNotReadUserData) {
 			log.Println(errCouldNotReadRequest, err)
 
 			http.Error(w, errCouldNotReadRequest.Error(), http.StatusInternalServerError)
@@ -140,96 +92,111 @@ func (b *Controller) HandleCreateUserData(w http.ResponseWriter, r *http.Request
 			return
 		}
 
-		var entityIdentifier models.ExportedEntityIdentifier
-		if err := json.Unmarshal(b, &entityIdentifier); err != nil {
-			log.Println(errCouldNotReadRequest, err)
+		log.Println(errCouldNotInsertIntoDB, err)
 
-			http.Error(w, errCouldNotReadRequest.Error(), http.StatusInternalServerError)
+		http.Error(w, errCouldNotInsertIntoDB.Error(), http.StatusInternalServerError)
 
-			return
-		}
+		return
+	}
+
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+func (b *Controller) HandleBackup(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
 
-		switch entityIdentifier.EntityName {
-		case EntityNameExportedJournalEntry:
-			var journalEntry models.ExportedJournalEntry
-			if err := json.Unmarshal(b, &journalEntry); err != nil {
-				log.Println(errCouldNotReadRequest, err)
+		http.Error(w, err.Error(), status)
 
-				http.Error(w, errCouldNotReadRequest.Error(), http.StatusInternalServerError)
+		return
+	} else if redirected {
+		return
+	}
 
-				return
-			}
+	redirected, journalKey, err := b.requireJournalKey(w, r, userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
 
-			if err := createJournalEntry(journalEntry); err != nil {
-				log.Println(errCouldNotInsertIntoDB, err)
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
 
-				http.Error(w, errCouldNotInsertIntoDB.Error(), http.StatusInternalServerError)
+		return
+	} else if redirected {
+		return
+	}
 
-				return
-			}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="senbara-forms-backup.zip"`)
 
-		case EntityNameExportedContact:
-			var contact models.ExportedContact
-			if err := json.Unmarshal(b, &contact); err != nil {
-				log.Println(errCouldNotReadRequest, err)
+	if err := userdata.ExportArchive(r.Context(), b.persister, journalKey, userData.Email, w); err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
 
-				http.Error(w, errCouldNotReadRequest.Error(), http.StatusInternalServerError)
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
 
-				return
-			}
+		return
+	}
+}
 
-			if err := createContact(contact); err != nil {
-				log.Println(errCouldNotInsertIntoDB, err)
+func (b *Controller) HandleRestoreBackup(w http.ResponseWriter, r *http.Request) {
+	redirected, userData, status, err := b.authorize(w, r)
+	if err != nil {
+		log.Println(err)
 
-				http.Error(w, errCouldNotInsertIntoDB.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), status)
 
-				return
-			}
+		return
+	} else if redirected {
+		return
+	}
+
+	redirected, journalKey, err := b.requireJournalKey(w, r, userData.Email)
+	if err != nil {
+		log.Println(errCouldNotFetchFromDB, err)
+
+		http.Error(w, errCouldNotFetchFromDB.Error(), http.StatusInternalServerError)
 
-		case EntityNameExportedDebt:
-			var debt models.ExportedDebt
-			if err := json.Unmarshal(b, &debt); err != nil {
-				log.Println(errCouldNotReadRequest, err)
+		return
+	} else if redirected {
+		return
+	}
 
-				http.Error(w, errCouldNotReadRequest.Error(), http.StatusInternalServerError)
+	r.Body = http.MaxBytesReader(w, r.Body, maxBackupUploadSize)
 
-				return
-			}
+	if err := r.ParseMultipartForm(maxBackupUploadMemory); err != nil {
+		log.Println(errCouldNotParseForm, err)
 
-			if err := createDebt(debt); err != nil {
-				log.Println(errCouldNotInsertIntoDB, err)
+		http.Error(w, errCouldNotParseForm.Error(), http.StatusInternalServerError)
 
-				http.Error(w, errCouldNotInsertIntoDB.Error(), http.StatusInternalServerError)
+		return
+	}
 
-				return
-			}
+	file, header, err := r.FormFile("backup")
+	if err != nil {
+		log.Println(errCouldNotReadRequest, err)
 
-		case EntityNameExportedActivity:
-			var activity models.ExportedActivity
-			if err := json.Unmarshal(b, &activity); err != nil {
-				log.Println(errCouldNotReadRequest, err)
+		http.Error(w, errCouldNotReadRequest.Error(), http.StatusUnprocessableEntity)
 
-				http.Error(w, errCouldNotReadRequest.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
 
-				return
-			}
+	if err := userdata.ImportArchive(r.Context(), b.persister, journalKey, userData.Email, file, header.Size); err != nil {
+		if errors.Is(err, userdata.ErrInvalidArchive) || errors.Is(err, userdata.ErrChecksumMismatch) {
+			log.Println(errInvalidBackup, err)
 
-			if err := createActivity(activity); err != nil {
-				log.Println(errCouldNotInsertIntoDB, err)
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
 
-				http.Error(w, errCouldNotInsertIntoDB.Error(), http.StatusInternalServerError)
+			return
+		}
 
-				return
-			}
+		if errors.Is(err, userdata.ErrCouldNotReadUserData) {
+			log.Println(errCouldNotReadRequest, err)
 
-		default:
-			log.Println("Skipping import error:", errUnknownEntityName, err)
+			http.Error(w, errCouldNotReadRequest.Error(), http.StatusInternalServerError)
 
-			continue
+			return
 		}
-	}
 
-	if err := commit(); err != nil {
 		log.Println(errCouldNotInsertIntoDB, err)
 
 		http.Error(w, errCouldNotInsertIntoDB.Error(), http.StatusInternalServerError)
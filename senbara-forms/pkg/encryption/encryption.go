@@ -0,0 +1,81 @@
+package encryption
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	saltSize = 16
+
+	// These are the parameters recommended in RFC 9106 for memory-constrained environments
+	argon2Time    = 3
+	argon2Memory  = 64 * 1024
+	argon2Threads = 4
+)
+
+var (
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+)
+
+// NewSalt returns a random salt to derive a key from a passphrase with
+func NewSalt() ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	return salt, nil
+}
+
+// DeriveKey derives a key from a passphrase with Argon2id
+func DeriveKey(passphrase string, salt []byte) []byte {
+	return argon2.IDKey([]byte(passphrase), salt, argon2Time, argon2Memory, argon2Threads, chacha20poly1305.KeySize)
+}
+
+// Encrypt encrypts and authenticates `plaintext` with XChaCha20-Poly1305. The `additionalData` is authenticated
+// but not encrypted; it has to be the same when decrypting, so a ciphertext can only be moved to a place that
+// uses the same additional data.
+// The random nonce is prepended to the ciphertext, which is encoded as base64 so that it can be stored as text.
+func Encrypt(key []byte, plaintext, additionalData string) (string, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(plaintext), []byte(additionalData))), nil
+}
+
+// Decrypt decrypts a ciphertext created by `Encrypt`. If the key or additional data are wrong or
+// the ciphertext was tampered with, `ErrInvalidCiphertext` is returned.
+func Decrypt(key []byte, ciphertext, additionalData string) (string, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return "", err
+	}
+
+	rawCiphertext, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", errors.Join(ErrInvalidCiphertext, err)
+	}
+
+	if len(rawCiphertext) < aead.NonceSize() {
+		return "", ErrInvalidCiphertext
+	}
+
+	plaintext, err := aead.Open(nil, rawCiphertext[:aead.NonceSize()], rawCiphertext[aead.NonceSize():], []byte(additionalData))
+	if err != nil {
+		return "", errors.Join(ErrInvalidCiphertext, err)
+	}
+
+	return string(plaintext), nil
+}
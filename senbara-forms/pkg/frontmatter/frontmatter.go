@@ -0,0 +1,207 @@
+package frontmatter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	delimiter = "---"
+
+	// DateFormat is used for dates when writing front matter, which is what Jekyll and Obsidian expect
+	DateFormat = time.RFC3339
+)
+
+var (
+	ErrInvalidFrontMatter = errors.New("invalid front matter")
+	ErrUnsupportedValue   = errors.New("unsupported front matter value")
+)
+
+// Field is a key-value pair of front matter. Supported values are strings, integers,
+// times and string slices; strings are always quoted so that they aren't interpreted
+// as other types by YAML parsers.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// Value is a parsed front matter value, which is either a scalar or a list of scalars
+type Value struct {
+	Scalar string
+	List   []string
+	IsList bool
+}
+
+// Strings returns the value as a list. Scalars are split at commas, which is how many tools store tags.
+func (v Value) Strings() []string {
+	if v.IsList {
+		return v.List
+	}
+
+	values := []string{}
+	for _, value := range strings.Split(v.Scalar, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// Write writes a Markdown document with YAML front matter containing `fields` to `w`
+func Write(w io.Writer, fields []Field, body string) error {
+	var buf bytes.Buffer
+
+	buf.WriteString(delimiter + "\n")
+
+	for _, field := range fields {
+		buf.WriteString(field.Key + ":")
+
+		switch value := field.Value.(type) {
+		case string:
+			buf.WriteString(" " + strconv.Quote(value))
+
+		case int:
+			buf.WriteString(" " + strconv.Itoa(value))
+
+		case int32:
+			buf.WriteString(" " + strconv.Itoa(int(value)))
+
+		case time.Time:
+			buf.WriteString(" " + value.Format(DateFormat))
+
+		case []string:
+			buf.WriteString(" [")
+			for i, v := range value {
+				if i > 0 {
+					buf.WriteString(", ")
+				}
+
+				buf.WriteString(strconv.Quote(v))
+			}
+			buf.WriteString("]")
+
+		default:
+			return fmt.Errorf("%w: %T", ErrUnsupportedValue, field.Value)
+		}
+
+		buf.WriteString("\n")
+	}
+
+	buf.WriteString(delimiter + "\n\n")
+	buf.WriteString(body)
+
+	if !strings.HasSuffix(body, "\n") {
+		buf.WriteString("\n")
+	}
+
+	_, err := w.Write(buf.Bytes())
+
+	return err
+}
+
+// Parse splits a Markdown document into its front matter and body and decodes the front matter as YAML.
+// Scalars keep their source text, so dates aren't interpreted, and nested values are skipped.
+// If the document has no front matter, the fields are empty.
+func Parse(source []byte) (map[string]Value, string, error) {
+	fields := map[string]Value{}
+
+	// Byte order marks are added by some editors on Windows
+	text := strings.ReplaceAll(strings.TrimPrefix(string(source), "\ufeff"), "\r\n", "\n")
+
+	rest, ok := strings.CutPrefix(text, delimiter+"\n")
+	if !ok {
+		return fields, text, nil
+	}
+
+	var (
+		lines  []string
+		body   string
+		closed bool
+	)
+	for rest != "" {
+		var line string
+		line, rest, _ = strings.Cut(rest, "\n")
+
+		if line == delimiter || line == "..." {
+			closed = true
+			body = rest
+
+			break
+		}
+
+		lines = append(lines, line)
+	}
+
+	if !closed {
+		return nil, "", fmt.Errorf("%w: missing closing delimiter", ErrInvalidFrontMatter)
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(strings.Join(lines, "\n")+"\n"), &document); err != nil {
+		return nil, "", errors.Join(ErrInvalidFrontMatter, err)
+	}
+
+	// Empty front matter has no content
+	if len(document.Content) == 0 {
+		return fields, strings.TrimLeft(body, "\n"), nil
+	}
+
+	mapping := resolveAlias(document.Content[0])
+	if mapping.Kind != yaml.MappingNode {
+		return nil, "", fmt.Errorf("%w: front matter is not a mapping", ErrInvalidFrontMatter)
+	}
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, node := resolveAlias(mapping.Content[i]), resolveAlias(mapping.Content[i+1])
+		if key.Kind != yaml.ScalarNode {
+			continue
+		}
+
+		switch node.Kind {
+		case yaml.ScalarNode:
+			fields[key.Value] = Value{
+				Scalar: scalarValue(node),
+			}
+
+		case yaml.SequenceNode:
+			values := []string{}
+			for _, item := range node.Content {
+				if item = resolveAlias(item); item.Kind == yaml.ScalarNode {
+					values = append(values, scalarValue(item))
+				}
+			}
+
+			fields[key.Value] = Value{
+				List:   values,
+				IsList: true,
+			}
+		}
+	}
+
+	return fields, strings.TrimLeft(body, "\n"), nil
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+
+	return node
+}
+
+// scalarValue returns the text of a scalar, which is empty for nulls like `~`
+func scalarValue(node *yaml.Node) string {
+	if node.ShortTag() == "!!null" {
+		return ""
+	}
+
+	return node.Value
+}
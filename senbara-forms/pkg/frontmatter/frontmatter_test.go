@@ -0,0 +1,225 @@
+package frontmatter
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		fields map[string]Value
+		body   string
+	}{
+		{
+			name:   "no front matter",
+			source: "# Hello\n\nWorld\n",
+			fields: map[string]Value{},
+			body:   "# Hello\n\nWorld\n",
+		},
+		{
+			name:   "empty front matter",
+			source: "---\n---\nWorld\n",
+			fields: map[string]Value{},
+			body:   "World\n",
+		},
+		{
+			name:   "plain, quoted and escaped scalars",
+			source: "---\ntitle: Hello world\nsingle: 'It''s here'\ndouble: \"Tab\\there \\\"quoted\\\"\"\nrating: 3\n---\n\nWorld\n",
+			fields: map[string]Value{
+				"title":  {Scalar: "Hello world"},
+				"single": {Scalar: "It's here"},
+				"double": {Scalar: "Tab\there \"quoted\""},
+				"rating": {Scalar: "3"},
+			},
+			body: "World\n",
+		},
+		{
+			name:   "dates keep their source text",
+			source: "---\ndate: 2026-10-18\ndatetime: 2026-10-18T12:00:00+02:00\n---\n",
+			fields: map[string]Value{
+				"date":     {Scalar: "2026-10-18"},
+				"datetime": {Scalar: "2026-10-18T12:00:00+02:00"},
+			},
+			body: "",
+		},
+		{
+			name:   "null and empty values",
+			source: "---\ntilde: ~\nnull: null\nempty:\n---\n",
+			fields: map[string]Value{
+				"tilde": {},
+				"null":  {},
+				"empty": {},
+			},
+			body: "",
+		},
+		{
+			name:   "literal and folded multi-line scalars",
+			source: "---\nliteral: |\n  first\n  second\nfolded: >\n  first\n  second\n---\n",
+			fields: map[string]Value{
+				"literal": {Scalar: "first\nsecond\n"},
+				"folded":  {Scalar: "first second\n"},
+			},
+			body: "",
+		},
+		{
+			name:   "comments",
+			source: "---\n# A comment\ntitle: Hello # A trailing comment\n---\n",
+			fields: map[string]Value{
+				"title": {Scalar: "Hello"},
+			},
+			body: "",
+		},
+		{
+			name:   "flow and block lists",
+			source: "---\nflow: [a, \"b, c\", 'd']\nblock:\n  - a\n  - b\nunindented:\n- a\nempty: []\n---\n",
+			fields: map[string]Value{
+				"flow":       {List: []string{"a", "b, c", "d"}, IsList: true},
+				"block":      {List: []string{"a", "b"}, IsList: true},
+				"unindented": {List: []string{"a"}, IsList: true},
+				"empty":      {List: []string{}, IsList: true},
+			},
+			body: "",
+		},
+		{
+			name:   "anchors and aliases",
+			source: "---\ntitle: &title Hello\nalias: *title\ntags: &tags [a, b]\nmoreTags: *tags\n---\n",
+			fields: map[string]Value{
+				"title":    {Scalar: "Hello"},
+				"alias":    {Scalar: "Hello"},
+				"tags":     {List: []string{"a", "b"}, IsList: true},
+				"moreTags": {List: []string{"a", "b"}, IsList: true},
+			},
+			body: "",
+		},
+		{
+			name:   "nested values are skipped",
+			source: "---\ntitle: Hello\nnested:\n  key: value\nlist:\n  - a\n  - key: value\n---\n",
+			fields: map[string]Value{
+				"title": {Scalar: "Hello"},
+				"list":  {List: []string{"a"}, IsList: true},
+			},
+			body: "",
+		},
+		{
+			name:   "byte order mark, CRLF line endings and dots as closing delimiter",
+			source: "\ufeff---\r\ntitle: Hello\r\n...\r\nWorld\r\n",
+			fields: map[string]Value{
+				"title": {Scalar: "Hello"},
+			},
+			body: "World\n",
+		},
+		{
+			name:   "thematic break in the body",
+			source: "---\ntitle: Hello\n---\nWorld\n\n---\n\nMore\n",
+			fields: map[string]Value{
+				"title": {Scalar: "Hello"},
+			},
+			body: "World\n\n---\n\nMore\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, body, err := Parse([]byte(tt.source))
+			if err != nil {
+				t.Fatalf("could not parse front matter: %v", err)
+			}
+
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Parse() fields = %#v, want %#v", fields, tt.fields)
+			}
+
+			if body != tt.body {
+				t.Errorf("Parse() body = %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{"missing closing delimiter", "---\ntitle: Hello\n"},
+		{"missing colon", "---\ntitle\n---\n"},
+		{"unclosed flow list", "---\ntags: [a, b\n---\n"},
+		{"unclosed quote", "---\ntitle: \"Hello\n---\n"},
+		{"unknown alias", "---\ntitle: *missing\n---\n"},
+		{"list instead of mapping", "---\n- a\n- b\n---\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := Parse([]byte(tt.source)); !errors.Is(err, ErrInvalidFrontMatter) {
+				t.Errorf("Parse() error = %v, want %v", err, ErrInvalidFrontMatter)
+			}
+		})
+	}
+}
+
+func TestValueStrings(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   Value
+		strings []string
+	}{
+		{"list", Value{List: []string{"a", "b, c"}, IsList: true}, []string{"a", "b, c"}},
+		{"comma-separated scalar", Value{Scalar: "a, b,,c "}, []string{"a", "b", "c"}},
+		{"empty scalar", Value{}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if strings := tt.value.Strings(); !reflect.DeepEqual(strings, tt.strings) {
+				t.Errorf("Strings() = %#v, want %#v", strings, tt.strings)
+			}
+		})
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	date := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	if err := Write(&buf, []Field{
+		{"title", "It's \"quoted\": yes\n# not a comment"},
+		{"date", date},
+		{"rating", int32(3)},
+		{"tags", []string{"a", "b, c", "ü"}},
+		{"empty", []string{}},
+	}, "# Hello"); err != nil {
+		t.Fatalf("could not write front matter: %v", err)
+	}
+
+	fields, body, err := Parse(buf.Bytes())
+	if err != nil {
+		t.Fatalf("could not parse written front matter: %v", err)
+	}
+
+	want := map[string]Value{
+		"title":  {Scalar: "It's \"quoted\": yes\n# not a comment"},
+		"date":   {Scalar: date.Format(DateFormat)},
+		"rating": {Scalar: "3"},
+		"tags":   {List: []string{"a", "b, c", "ü"}, IsList: true},
+		"empty":  {List: []string{}, IsList: true},
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("Parse() fields = %#v, want %#v", fields, want)
+	}
+
+	if body != "# Hello\n" {
+		t.Errorf("Parse() body = %q, want %q", body, "# Hello\n")
+	}
+}
+
+func TestWriteUnsupportedValue(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, []Field{{"rating", 3.5}}, ""); !errors.Is(err, ErrUnsupportedValue) {
+		t.Errorf("Write() error = %v, want %v", err, ErrUnsupportedValue)
+	}
+}
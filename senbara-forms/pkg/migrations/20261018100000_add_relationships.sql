@@ -0,0 +1,13 @@
+-- +goose Up
+create table relationships (
+    id serial primary key,
+    contact_id integer not null,
+    related_contact_id integer not null,
+    kind text not null,
+    foreign key (contact_id) references contacts (id),
+    foreign key (related_contact_id) references contacts (id),
+    unique (contact_id, related_contact_id, kind),
+    check (contact_id <> related_contact_id)
+);
+-- +goose Down
+drop table relationships;
\ No newline at end of file
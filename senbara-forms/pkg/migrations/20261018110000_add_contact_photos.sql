@@ -0,0 +1,14 @@
+-- +goose Up
+create table contact_photos (
+    id serial primary key,
+    contact_id integer not null,
+    size integer not null,
+    content_type text not null,
+    data bytea not null,
+    namespace text not null,
+    created_at timestamp not null default now(),
+    foreign key (contact_id) references contacts (id),
+    unique (contact_id, size)
+);
+-- +goose Down
+drop table contact_photos;
\ No newline at end of file
@@ -0,0 +1,11 @@
+-- +goose Up
+create table trash_items (
+    id serial primary key,
+    entity_name text not null,
+    title text not null,
+    data jsonb not null,
+    namespace text not null,
+    deleted_at timestamp not null default now()
+);
+-- +goose Down
+drop table trash_items;
\ No newline at end of file
@@ -0,0 +1,21 @@
+-- +goose Up
+create table custom_fields (
+    id serial primary key,
+    name text not null,
+    kind text not null,
+    options text [] not null default '{}',
+    namespace text not null,
+    unique (namespace, name)
+);
+create table custom_field_values (
+    id serial primary key,
+    contact_id integer not null,
+    custom_field_id integer not null,
+    value text not null,
+    foreign key (contact_id) references contacts (id),
+    foreign key (custom_field_id) references custom_fields (id),
+    unique (contact_id, custom_field_id)
+);
+-- +goose Down
+drop table custom_field_values;
+drop table custom_fields;
\ No newline at end of file
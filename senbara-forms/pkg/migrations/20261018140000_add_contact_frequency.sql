@@ -0,0 +1,5 @@
+-- +goose Up
+alter table contacts
+add column contact_frequency text default '' not null;
+-- +goose Down
+alter table contacts drop column contact_frequency;
\ No newline at end of file
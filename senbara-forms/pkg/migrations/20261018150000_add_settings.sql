@@ -0,0 +1,7 @@
+-- +goose Up
+create table settings (
+    namespace text primary key,
+    time_zone text not null default 'UTC'
+);
+-- +goose Down
+drop table settings;
\ No newline at end of file
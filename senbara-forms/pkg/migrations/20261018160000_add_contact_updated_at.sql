@@ -0,0 +1,5 @@
+-- +goose Up
+alter table contacts
+add column updated_at timestamp not null default now();
+-- +goose Down
+alter table contacts drop column updated_at;
\ No newline at end of file
@@ -0,0 +1,121 @@
+-- +goose Up
+alter table settings
+add column search_language text not null default 'simple';
+-- +goose StatementBegin
+create function get_search_config(namespace text) returns regconfig as $$
+select coalesce(
+        (
+            select settings.search_language::regconfig
+            from settings
+            where settings.namespace = $1
+        ),
+        'simple'::regconfig
+    );
+$$ language sql stable;
+-- +goose StatementEnd
+alter table journal_entries
+add column search_vector tsvector;
+-- +goose StatementBegin
+create function update_journal_entries_search_vector() returns trigger as $$
+declare config regconfig := get_search_config(new.namespace);
+begin new.search_vector := setweight(to_tsvector(config, new.title), 'A') || setweight(to_tsvector(config, new.body), 'B');
+return new;
+end;
+$$ language plpgsql;
+-- +goose StatementEnd
+create trigger update_journal_entries_search_vector before
+insert
+    or
+update on journal_entries for each row execute function update_journal_entries_search_vector();
+create index journal_entries_search_vector_idx on journal_entries using gin (search_vector);
+alter table contacts
+add column search_vector tsvector;
+-- +goose StatementBegin
+create function update_contacts_search_vector() returns trigger as $$
+declare config regconfig := get_search_config(new.namespace);
+begin new.search_vector := setweight(
+    to_tsvector(
+        config,
+        new.first_name || ' ' || new.last_name || ' ' || new.nickname
+    ),
+    'A'
+) || setweight(to_tsvector(config, new.notes), 'B');
+return new;
+end;
+$$ language plpgsql;
+-- +goose StatementEnd
+create trigger update_contacts_search_vector before
+insert
+    or
+update on contacts for each row execute function update_contacts_search_vector();
+create index contacts_search_vector_idx on contacts using gin (search_vector);
+alter table debts
+add column search_vector tsvector;
+-- +goose StatementBegin
+create function update_debts_search_vector() returns trigger as $$
+declare config regconfig := get_search_config(
+        (
+            select contacts.namespace
+            from contacts
+            where contacts.id = new.contact_id
+        )
+    );
+begin new.search_vector := setweight(to_tsvector(config, new.description), 'A');
+return new;
+end;
+$$ language plpgsql;
+-- +goose StatementEnd
+create trigger update_debts_search_vector before
+insert
+    or
+update on debts for each row execute function update_debts_search_vector();
+create index debts_search_vector_idx on debts using gin (search_vector);
+alter table activities
+add column search_vector tsvector;
+-- +goose StatementBegin
+create function update_activities_search_vector() returns trigger as $$
+declare config regconfig := get_search_config(
+        (
+            select contacts.namespace
+            from contacts
+            where contacts.id = new.contact_id
+        )
+    );
+begin new.search_vector := setweight(to_tsvector(config, new.name), 'A') || setweight(to_tsvector(config, new.description), 'B');
+return new;
+end;
+$$ language plpgsql;
+-- +goose StatementEnd
+create trigger update_activities_search_vector before
+insert
+    or
+update on activities for each row execute function update_activities_search_vector();
+create index activities_search_vector_idx on activities using gin (search_vector);
+-- Setting the vectors to null fires the triggers, which index the existing rows
+update journal_entries
+set search_vector = null;
+update contacts
+set search_vector = null;
+update debts
+set search_vector = null;
+update activities
+set search_vector = null;
+-- +goose Down
+drop index activities_search_vector_idx;
+drop trigger update_activities_search_vector on activities;
+drop function update_activities_search_vector;
+alter table activities drop column search_vector;
+drop index debts_search_vector_idx;
+drop trigger update_debts_search_vector on debts;
+drop function update_debts_search_vector;
+alter table debts drop column search_vector;
+drop index contacts_search_vector_idx;
+drop trigger update_contacts_search_vector on contacts;
+drop function update_contacts_search_vector;
+alter table contacts drop column search_vector;
+drop index journal_entries_search_vector_idx;
+drop trigger update_journal_entries_search_vector on journal_entries;
+drop function update_journal_entries_search_vector;
+alter table journal_entries drop column search_vector;
+drop function get_search_config;
+alter table settings drop column search_language;
\ No newline at end of file
@@ -0,0 +1,10 @@
+-- +goose Up
+create table journal_entry_tags (
+    journal_entry_id integer not null,
+    name text not null,
+    foreign key (journal_entry_id) references journal_entries (id),
+    primary key (journal_entry_id, name)
+);
+create index journal_entry_tags_name_idx on journal_entry_tags (name);
+-- +goose Down
+drop table journal_entry_tags;
\ No newline at end of file
@@ -0,0 +1,12 @@
+-- +goose Up
+create table revisions (
+    id serial primary key,
+    entity_name text not null,
+    entity_id integer not null,
+    data jsonb not null,
+    namespace text not null,
+    created_at timestamp not null default now()
+);
+create index revisions_entity_idx on revisions (namespace, entity_name, entity_id);
+-- +goose Down
+drop table revisions;
\ No newline at end of file
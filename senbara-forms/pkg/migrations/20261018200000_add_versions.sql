@@ -0,0 +1,14 @@
+-- +goose Up
+alter table journal_entries
+add column version integer not null default 1;
+alter table contacts
+add column version integer not null default 1;
+alter table debts
+add column version integer not null default 1;
+alter table activities
+add column version integer not null default 1;
+-- +goose Down
+alter table activities drop column version;
+alter table debts drop column version;
+alter table contacts drop column version;
+alter table journal_entries drop column version;
\ No newline at end of file
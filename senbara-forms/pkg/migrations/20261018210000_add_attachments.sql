@@ -0,0 +1,15 @@
+-- +goose Up
+create table attachments (
+    id serial primary key,
+    journal_entry_id integer not null,
+    name text not null,
+    content_type text not null,
+    size integer not null,
+    blob_key text not null,
+    namespace text not null,
+    created_at timestamp not null default now(),
+    foreign key (journal_entry_id) references journal_entries (id)
+);
+create index attachments_journal_entry_id_idx on attachments (journal_entry_id);
+-- +goose Down
+drop table attachments;
\ No newline at end of file
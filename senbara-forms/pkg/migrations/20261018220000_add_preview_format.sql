@@ -0,0 +1,5 @@
+-- +goose Up
+alter table settings
+add column preview_format text not null default 'text';
+-- +goose Down
+alter table settings drop column preview_format;
\ No newline at end of file
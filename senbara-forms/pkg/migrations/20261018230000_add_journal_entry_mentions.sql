@@ -0,0 +1,11 @@
+-- +goose Up
+create table journal_entry_mentions (
+    journal_entry_id integer not null,
+    contact_id integer not null,
+    foreign key (journal_entry_id) references journal_entries (id),
+    foreign key (contact_id) references contacts (id),
+    primary key (journal_entry_id, contact_id)
+);
+create index journal_entry_mentions_contact_id_idx on journal_entry_mentions (contact_id);
+-- +goose Down
+drop table journal_entry_mentions;
\ No newline at end of file
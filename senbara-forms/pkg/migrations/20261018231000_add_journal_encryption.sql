@@ -0,0 +1,33 @@
+-- +goose Up
+alter table settings
+add column journal_encrypted boolean not null default false,
+    add column journal_encryption_salt bytea not null default '',
+    add column journal_encryption_check text not null default '';
+-- +goose StatementBegin
+create or replace function update_journal_entries_search_vector() returns trigger as $$
+declare config regconfig := get_search_config(new.namespace);
+begin if exists (
+    select 1
+    from settings
+    where settings.namespace = new.namespace
+        and settings.journal_encrypted
+) then new.search_vector := null;
+return new;
+end if;
+new.search_vector := setweight(to_tsvector(config, new.title), 'A') || setweight(to_tsvector(config, new.body), 'B');
+return new;
+end;
+$$ language plpgsql;
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+create or replace function update_journal_entries_search_vector() returns trigger as $$
+declare config regconfig := get_search_config(new.namespace);
+begin new.search_vector := setweight(to_tsvector(config, new.title), 'A') || setweight(to_tsvector(config, new.body), 'B');
+return new;
+end;
+$$ language plpgsql;
+-- +goose StatementEnd
+alter table settings drop column journal_encrypted,
+    drop column journal_encryption_salt,
+    drop column journal_encryption_check;
\ No newline at end of file
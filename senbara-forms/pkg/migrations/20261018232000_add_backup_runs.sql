@@ -0,0 +1,11 @@
+-- +goose Up
+create table backup_runs (
+    namespace text primary key,
+    ran_at timestamptz not null,
+    file_name text not null default '',
+    size bigint not null default 0,
+    error text not null default '',
+    last_succeeded_at timestamptz
+);
+-- +goose Down
+drop table backup_runs;
\ No newline at end of file
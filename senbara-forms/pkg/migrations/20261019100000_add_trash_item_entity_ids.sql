@@ -0,0 +1,17 @@
+-- +goose Up
+alter table trash_items
+add column entity_id integer;
+update trash_items
+set entity_id = (
+        case
+            when entity_name = 'contact' then data->'contact'->>'id'
+            else data->>'id'
+        end
+    )::integer;
+alter table trash_items
+alter column entity_id
+set not null;
+create index trash_items_entity_idx on trash_items (namespace, entity_name, entity_id);
+-- +goose Down
+drop index trash_items_entity_idx;
+alter table trash_items drop column entity_id;
@@ -0,0 +1,5 @@
+-- +goose Up
+alter table backup_runs
+add column journal_skipped boolean not null default false;
+-- +goose Down
+alter table backup_runs drop column journal_skipped;
\ No newline at end of file
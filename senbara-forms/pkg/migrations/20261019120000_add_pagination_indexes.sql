@@ -0,0 +1,10 @@
+-- +goose Up
+create index journal_entries_namespace_date_id_idx on journal_entries (namespace, date, id);
+create index journal_entries_namespace_rating_id_idx on journal_entries (namespace, rating, id);
+create index contacts_namespace_first_name_last_name_id_idx on contacts (namespace, first_name, last_name, id);
+create index contacts_namespace_updated_at_id_idx on contacts (namespace, updated_at, id);
+-- +goose Down
+drop index contacts_namespace_updated_at_id_idx;
+drop index contacts_namespace_first_name_last_name_id_idx;
+drop index journal_entries_namespace_rating_id_idx;
+drop index journal_entries_namespace_date_id_idx;
\ No newline at end of file
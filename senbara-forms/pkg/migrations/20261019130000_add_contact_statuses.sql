@@ -0,0 +1,24 @@
+-- +goose Up
+create view contact_statuses as
+select contacts.id as contact_id,
+    contacts.namespace,
+    last_activities.last_contacted,
+    contacts.contact_frequency <> ''
+    and (
+        last_activities.last_contacted is null
+        or last_activities.last_contacted + case
+            contacts.contact_frequency
+            when 'weekly' then interval '7 days'
+            when 'monthly' then interval '1 month'
+            when 'quarterly' then interval '3 months'
+        end < now()
+    ) as overdue
+from contacts
+    cross join lateral (
+        select max(activities.date) as last_contacted
+        from activities
+        where activities.contact_id = contacts.id
+            and activities.date <= now()
+    ) as last_activities;
+-- +goose Down
+drop view contact_statuses;
\ No newline at end of file
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+create function get_next_birthday(birthday date, today date) returns date as $$
+select case
+        when (
+            birthday + make_interval(
+                years => (date_part('year', today) - date_part('year', birthday))::integer
+            )
+        )::date >= today then (
+            birthday + make_interval(
+                years => (date_part('year', today) - date_part('year', birthday))::integer
+            )
+        )::date
+        else (
+            birthday + make_interval(
+                years => (date_part('year', today) - date_part('year', birthday))::integer + 1
+            )
+        )::date
+    end;
+$$ language sql immutable;
+-- +goose StatementEnd
+-- +goose Down
+drop function get_next_birthday;
\ No newline at end of file
@@ -3,11 +3,13 @@ package models
 import "github.com/pojntfx/senbara/senbara-forms/pkg/tables"
 
 type (
-	CreateActivityParams        = tables.CreateActivityParams
-	GetActivitiesParams         = tables.GetActivitiesParams
-	DeleteActivityParams        = tables.DeleteActivityParams
-	GetActivityAndContactParams = tables.GetActivityAndContactParams
-	UpdateActivityParams        = tables.UpdateActivityParams
+	CreateActivityParams          = tables.CreateActivityParams
+	GetActivitiesParams           = tables.GetActivitiesParams
+	DeleteActivityParams          = tables.DeleteActivityParams
+	GetActivityAndContactParams   = tables.GetActivityAndContactParams
+	UpdateActivityParams          = tables.UpdateActivityParams
+	MoveActivitiesToContactParams = tables.MoveActivitiesToContactParams
+	RestoreActivityParams         = tables.RestoreActivityParams
 )
 
 type (
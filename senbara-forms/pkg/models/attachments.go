@@ -0,0 +1,20 @@
+package models
+
+import "github.com/pojntfx/senbara/senbara-forms/pkg/tables"
+
+type (
+	CreateAttachmentParams                 = tables.CreateAttachmentParams
+	GetAttachmentParams                    = tables.GetAttachmentParams
+	GetAttachmentsParams                   = tables.GetAttachmentsParams
+	DeleteAttachmentParams                 = tables.DeleteAttachmentParams
+	DeleteAttachmentsForJournalEntryParams = tables.DeleteAttachmentsForJournalEntryParams
+	RestoreAttachmentParams                = tables.RestoreAttachmentParams
+)
+
+type (
+	Attachment = tables.Attachment
+)
+
+type (
+	GetAttachmentsExportForNamespaceRow = tables.GetAttachmentsExportForNamespaceRow
+)
@@ -0,0 +1,11 @@
+package models
+
+import "github.com/pojntfx/senbara/senbara-forms/pkg/tables"
+
+type (
+	UpdateBackupRunParams = tables.UpdateBackupRunParams
+)
+
+type (
+	BackupRun = tables.BackupRun
+)
@@ -0,0 +1,17 @@
+package models
+
+import "github.com/pojntfx/senbara/senbara-forms/pkg/tables"
+
+type (
+	CreateContactPhotoParams         = tables.CreateContactPhotoParams
+	GetContactPhotoParams            = tables.GetContactPhotoParams
+	GetContactPhotoSizesParams       = tables.GetContactPhotoSizesParams
+	DeleteContactPhotosParams        = tables.DeleteContactPhotosParams
+	MoveContactPhotosToContactParams = tables.MoveContactPhotosToContactParams
+	GetContactPhotosParams           = tables.GetContactPhotosParams
+)
+
+type (
+	GetContactPhotoRow  = tables.GetContactPhotoRow
+	GetContactPhotosRow = tables.GetContactPhotosRow
+)
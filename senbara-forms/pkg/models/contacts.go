@@ -3,13 +3,24 @@ package models
 import "github.com/pojntfx/senbara/senbara-forms/pkg/tables"
 
 type (
-	CreateContactParams         = tables.CreateContactParams
-	GetContactParams            = tables.GetContactParams
-	DeleteContactParams         = tables.DeleteContactParams
-	DeleteDebtsForContactParams = tables.DeleteDebtsForContactParams
-	UpdateContactParams         = tables.UpdateContactParams
+	CreateContactParams             = tables.CreateContactParams
+	GetContactParams                = tables.GetContactParams
+	DeleteContactParams             = tables.DeleteContactParams
+	DeleteDebtsForContactParams     = tables.DeleteDebtsForContactParams
+	UpdateContactParams             = tables.UpdateContactParams
+	DeleteActivitesForContactParams = tables.DeleteActivitesForContactParams
+	RestoreContactParams            = tables.RestoreContactParams
+
+	GetContactIDByNameParams         = tables.GetContactIDByNameParams
+	GetContactStatusParams           = tables.GetContactStatusParams
+	GetContactsByNameAscParams       = tables.GetContactsByNameAscParams
+	GetContactsByNameDescParams      = tables.GetContactsByNameDescParams
+	GetContactsByUpdatedAtAscParams  = tables.GetContactsByUpdatedAtAscParams
+	GetContactsByUpdatedAtDescParams = tables.GetContactsByUpdatedAtDescParams
 )
 
 type (
-	Contact = tables.Contact
+	Contact               = tables.Contact
+	ContactStatus         = tables.ContactStatus
+	GetOverdueContactsRow = tables.GetOverdueContactsRow
 )
@@ -0,0 +1,20 @@
+package models
+
+import "github.com/pojntfx/senbara/senbara-forms/pkg/tables"
+
+type (
+	CreateCustomFieldParams                     = tables.CreateCustomFieldParams
+	EnsureCustomFieldParams                     = tables.EnsureCustomFieldParams
+	DeleteCustomFieldParams                     = tables.DeleteCustomFieldParams
+	GetCustomFieldValuesParams                  = tables.GetCustomFieldValuesParams
+	CreateCustomFieldValueParams                = tables.CreateCustomFieldValueParams
+	MoveCustomFieldValuesToContactParams        = tables.MoveCustomFieldValuesToContactParams
+	DeleteCustomFieldValuesForContactParams     = tables.DeleteCustomFieldValuesForContactParams
+	DeleteCustomFieldValuesForCustomFieldParams = tables.DeleteCustomFieldValuesForCustomFieldParams
+)
+
+type (
+	CustomField             = tables.CustomField
+	CustomFieldValue        = tables.CustomFieldValue
+	GetCustomFieldValuesRow = tables.GetCustomFieldValuesRow
+)
@@ -0,0 +1,28 @@
+package models
+
+import "github.com/pojntfx/senbara/senbara-forms/pkg/tables"
+
+type (
+	GetRecentJournalEntriesParams    = tables.GetRecentJournalEntriesParams
+	GetUpcomingActivitiesParams      = tables.GetUpcomingActivitiesParams
+	GetRecentlyUpdatedContactsParams = tables.GetRecentlyUpdatedContactsParams
+	GetUpcomingBirthdaysParams       = tables.GetUpcomingBirthdaysParams
+)
+
+type (
+	GetRecentJournalEntriesRow    = tables.GetRecentJournalEntriesRow
+	GetDebtBalancesRow            = tables.GetDebtBalancesRow
+	GetUpcomingActivitiesRow      = tables.GetUpcomingActivitiesRow
+	GetRecentlyUpdatedContactsRow = tables.GetRecentlyUpdatedContactsRow
+)
+
+type (
+	Dashboard = struct {
+		RecentJournalEntries    []GetRecentJournalEntriesRow
+		DebtBalances            []GetDebtBalancesRow
+		UpcomingActivities      []GetUpcomingActivitiesRow
+		RecentlyUpdatedContacts []GetRecentlyUpdatedContactsRow
+		OverdueContacts         []GetOverdueContactsRow
+		UpcomingBirthdays       []Contact
+	}
+)
@@ -3,11 +3,13 @@ package models
 import "github.com/pojntfx/senbara/senbara-forms/pkg/tables"
 
 type (
-	CreateDebtParams        = tables.CreateDebtParams
-	GetDebtsParams          = tables.GetDebtsParams
-	SettleDebtParams        = tables.SettleDebtParams
-	GetDebtAndContactParams = tables.GetDebtAndContactParams
-	UpdateDebtParams        = tables.UpdateDebtParams
+	CreateDebtParams         = tables.CreateDebtParams
+	GetDebtsParams           = tables.GetDebtsParams
+	SettleDebtParams         = tables.SettleDebtParams
+	GetDebtAndContactParams  = tables.GetDebtAndContactParams
+	UpdateDebtParams         = tables.UpdateDebtParams
+	MoveDebtsToContactParams = tables.MoveDebtsToContactParams
+	RestoreDebtParams        = tables.RestoreDebtParams
 )
 
 type (
@@ -3,12 +3,23 @@ package models
 import "github.com/pojntfx/senbara/senbara-forms/pkg/tables"
 
 type (
-	CreateJournalEntryParams = tables.CreateJournalEntryParams
-	DeleteJournalEntryParams = tables.DeleteJournalEntryParams
-	GetJournalEntryParams    = tables.GetJournalEntryParams
-	UpdateJournalEntryParams = tables.UpdateJournalEntryParams
+	CreateJournalEntryParams  = tables.CreateJournalEntryParams
+	DeleteJournalEntryParams  = tables.DeleteJournalEntryParams
+	GetJournalEntryParams     = tables.GetJournalEntryParams
+	UpdateJournalEntryParams  = tables.UpdateJournalEntryParams
+	RestoreJournalEntryParams = tables.RestoreJournalEntryParams
+
+	UpdateJournalEntryBodyParams         = tables.UpdateJournalEntryBodyParams
+	UpdateJournalEntryTitleAndBodyParams = tables.UpdateJournalEntryTitleAndBodyParams
+
+	GetJournalEntriesByDateAscParams    = tables.GetJournalEntriesByDateAscParams
+	GetJournalEntriesByDateDescParams   = tables.GetJournalEntriesByDateDescParams
+	GetJournalEntriesByRatingAscParams  = tables.GetJournalEntriesByRatingAscParams
+	GetJournalEntriesByRatingDescParams = tables.GetJournalEntriesByRatingDescParams
 )
 
 type (
 	JournalEntry = tables.JournalEntry
+
+	GetJournalEntryRatingsRow = tables.GetJournalEntryRatingsRow
 )
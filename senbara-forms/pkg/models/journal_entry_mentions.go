@@ -0,0 +1,16 @@
+package models
+
+import "github.com/pojntfx/senbara/senbara-forms/pkg/tables"
+
+type (
+	CreateJournalEntryMentionsParams           = tables.CreateJournalEntryMentionsParams
+	CreateJournalEntryMentionsForContactParams = tables.CreateJournalEntryMentionsForContactParams
+	DeleteJournalEntryMentionsParams           = tables.DeleteJournalEntryMentionsParams
+	DeleteJournalEntryMentionsForContactParams = tables.DeleteJournalEntryMentionsForContactParams
+	MoveJournalEntryMentionsToContactParams    = tables.MoveJournalEntryMentionsToContactParams
+	GetJournalEntriesMentioningContactParams   = tables.GetJournalEntriesMentioningContactParams
+)
+
+type (
+	GetJournalEntriesMentioningContactRow = tables.GetJournalEntriesMentioningContactRow
+)
@@ -0,0 +1,14 @@
+package models
+
+import "github.com/pojntfx/senbara/senbara-forms/pkg/tables"
+
+type (
+	CreateJournalEntryTagParams                = tables.CreateJournalEntryTagParams
+	DeleteJournalEntryTagsParams               = tables.DeleteJournalEntryTagsParams
+	GetJournalEntryTagsParams                  = tables.GetJournalEntryTagsParams
+	GetJournalEntryTagsForJournalEntriesParams = tables.GetJournalEntryTagsForJournalEntriesParams
+)
+
+type (
+	GetJournalTagsRow = tables.GetJournalTagsRow
+)
@@ -0,0 +1,18 @@
+package models
+
+import "github.com/pojntfx/senbara/senbara-forms/pkg/tables"
+
+type (
+	CreateRelationshipParams                 = tables.CreateRelationshipParams
+	GetRelationshipsParams                   = tables.GetRelationshipsParams
+	GetRelationshipParams                    = tables.GetRelationshipParams
+	DeleteRelationshipParams                 = tables.DeleteRelationshipParams
+	DeleteRelationshipsForContactParams      = tables.DeleteRelationshipsForContactParams
+	DeleteRelationshipsBetweenContactsParams = tables.DeleteRelationshipsBetweenContactsParams
+	CopyRelationshipsToContactParams         = tables.CopyRelationshipsToContactParams
+)
+
+type (
+	Relationship        = tables.Relationship
+	GetRelationshipsRow = tables.GetRelationshipsRow
+)
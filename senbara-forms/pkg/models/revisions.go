@@ -0,0 +1,18 @@
+package models
+
+import "github.com/pojntfx/senbara/senbara-forms/pkg/tables"
+
+type (
+	CreateRevisionParams              = tables.CreateRevisionParams
+	GetRevisionsParams                = tables.GetRevisionsParams
+	GetRevisionParams                 = tables.GetRevisionParams
+	DeleteRevisionsForEntityParams    = tables.DeleteRevisionsForEntityParams
+	DeleteRevisionsForTrashItemParams = tables.DeleteRevisionsForTrashItemParams
+	GetRevisionsForEntityNameParams   = tables.GetRevisionsForEntityNameParams
+	UpdateRevisionDataParams          = tables.UpdateRevisionDataParams
+)
+
+type (
+	Revision        = tables.Revision
+	GetRevisionsRow = tables.GetRevisionsRow
+)
@@ -0,0 +1,11 @@
+package models
+
+import "github.com/pojntfx/senbara/senbara-forms/pkg/tables"
+
+type (
+	SearchParams = tables.SearchParams
+)
+
+type (
+	SearchRow = tables.SearchRow
+)
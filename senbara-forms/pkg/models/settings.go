@@ -0,0 +1,12 @@
+package models
+
+import "github.com/pojntfx/senbara/senbara-forms/pkg/tables"
+
+type (
+	UpdateSettingsParams          = tables.UpdateSettingsParams
+	UpdateJournalEncryptionParams = tables.UpdateJournalEncryptionParams
+)
+
+type (
+	Setting = tables.Setting
+)
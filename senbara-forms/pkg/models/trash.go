@@ -0,0 +1,42 @@
+package models
+
+import "github.com/pojntfx/senbara/senbara-forms/pkg/tables"
+
+type (
+	CreateTrashItemParams            = tables.CreateTrashItemParams
+	GetTrashItemParams               = tables.GetTrashItemParams
+	GetTrashItemForEntityParams      = tables.GetTrashItemForEntityParams
+	DeleteTrashItemParams            = tables.DeleteTrashItemParams
+	GetTrashItemsForEntityNameParams = tables.GetTrashItemsForEntityNameParams
+	UpdateTrashItemParams            = tables.UpdateTrashItemParams
+)
+
+type (
+	TrashItem        = tables.TrashItem
+	GetTrashItemsRow = tables.GetTrashItemsRow
+)
+
+type (
+	TrashedRelationship = struct {
+		RelatedContactID int32  `json:"relatedContactId"`
+		Kind             string `json:"kind"`
+	}
+
+	// TrashedJournalEntry embeds the journal entry so that trash items from before attachments were added still decode
+	TrashedJournalEntry = struct {
+		ExportedJournalEntry
+
+		Attachments []ExportedAttachment `json:"attachments,omitempty"`
+	}
+
+	TrashedContact = struct {
+		Contact       ExportedContact        `json:"contact"`
+		Debts         []ExportedDebt         `json:"debts"`
+		Activities    []ExportedActivity     `json:"activities"`
+		Relationships []TrashedRelationship  `json:"relationships"`
+		ContactPhotos []ExportedContactPhoto `json:"contactPhotos"`
+
+		// MentioningJournalEntryIDs are the journal entries that mentioned the contact when it was deleted
+		MentioningJournalEntryIDs []int32 `json:"mentioningJournalEntryIds,omitempty"`
+	}
+)
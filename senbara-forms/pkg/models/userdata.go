@@ -21,21 +21,25 @@ type (
 		Body      string    `json:"body"`
 		Rating    int32     `json:"rating"`
 		Namespace string    `json:"namespace"`
+		Tags      []string  `json:"tags,omitempty"`
 	}
 
 	ExportedContact = struct {
 		ExportedEntityIdentifier
 
-		ID        int32        `json:"id"`
-		FirstName string       `json:"firstName"`
-		LastName  string       `json:"lastName"`
-		Nickname  string       `json:"nickname"`
-		Email     string       `json:"email"`
-		Pronouns  string       `json:"pronouns"`
-		Namespace string       `json:"namespace"`
-		Birthday  sql.NullTime `json:"birthday"`
-		Address   string       `json:"address"`
-		Notes     string       `json:"notes"`
+		ID               int32        `json:"id"`
+		FirstName        string       `json:"firstName"`
+		LastName         string       `json:"lastName"`
+		Nickname         string       `json:"nickname"`
+		Email            string       `json:"email"`
+		Pronouns         string       `json:"pronouns"`
+		Namespace        string       `json:"namespace"`
+		Birthday         sql.NullTime `json:"birthday"`
+		Address          string       `json:"address"`
+		Notes            string       `json:"notes"`
+		ContactFrequency string       `json:"contactFrequency"`
+
+		CustomFields map[string]string `json:"customFields,omitempty"`
 	}
 
 	ExportedDebt = struct {
@@ -57,4 +61,44 @@ type (
 		Description string        `json:"description"`
 		ContactID   sql.NullInt32 `json:"contactId"`
 	}
+
+	ExportedContactPhoto = struct {
+		ExportedEntityIdentifier
+
+		ID          int32         `json:"id"`
+		Size        int32         `json:"size"`
+		ContentType string        `json:"contentType"`
+		Data        []byte        `json:"data"`
+		Blob        string        `json:"blob,omitempty"`
+		ContactID   sql.NullInt32 `json:"contactId"`
+	}
+
+	ExportedAttachment = struct {
+		ExportedEntityIdentifier
+
+		ID             int32         `json:"id"`
+		Name           string        `json:"name"`
+		ContentType    string        `json:"contentType"`
+		Data           []byte        `json:"data"`
+		Blob           string        `json:"blob,omitempty"`
+		JournalEntryID sql.NullInt32 `json:"journalEntryId"`
+	}
+
+	ExportedRelationship = struct {
+		ExportedEntityIdentifier
+
+		ID               int32         `json:"id"`
+		Kind             string        `json:"kind"`
+		ContactID        sql.NullInt32 `json:"contactId"`
+		RelatedContactID sql.NullInt32 `json:"relatedContactId"`
+	}
+
+	ExportedCustomField = struct {
+		ExportedEntityIdentifier
+
+		ID      int32    `json:"id"`
+		Name    string   `json:"name"`
+		Kind    string   `json:"kind"`
+		Options []string `json:"options"`
+	}
 )
@@ -2,6 +2,7 @@ package persisters
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
@@ -46,12 +47,57 @@ func (p *Persister) DeleteActivity(
 	contactID int32,
 	namespace string,
 ) error {
-	return p.queries.DeleteActivity(ctx, models.DeleteActivityParams{
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := p.queries.WithTx(tx)
+
+	activity, err := qtx.GetActivityAndContact(ctx, models.GetActivityAndContactParams{
 		ID_2: id,
 
 		ID:        contactID,
 		Namespace: namespace,
 	})
+	if err != nil {
+		return err
+	}
+
+	if err := createTrashItem(
+		ctx,
+		qtx,
+
+		TrashEntityNameActivity,
+		activity.ActivityID,
+		activity.Name,
+		models.ExportedActivity{
+			ID:          activity.ActivityID,
+			Name:        activity.Name,
+			Date:        activity.Date,
+			Description: activity.Description,
+			ContactID: sql.NullInt32{
+				Int32: activity.ContactID,
+				Valid: true,
+			},
+		},
+
+		namespace,
+	); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteActivity(ctx, models.DeleteActivityParams{
+		ID_2: id,
+
+		ID:        contactID,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (p *Persister) GetActivityAndContact(
@@ -73,7 +119,8 @@ func (p *Persister) GetActivityAndContact(
 func (p *Persister) UpdateActivity(
 	ctx context.Context,
 
-	id int32,
+	id,
+	version int32,
 
 	contactID int32,
 	namespace string,
@@ -82,14 +129,34 @@ func (p *Persister) UpdateActivity(
 	date time.Time,
 	description string,
 ) error {
-	return p.queries.UpdateActivity(ctx, models.UpdateActivityParams{
-		ID_2: id,
+	rows, err := p.queries.UpdateActivity(ctx, models.UpdateActivityParams{
+		ID:      id,
+		Version: version,
 
-		ID:        contactID,
+		ContactID: contactID,
 		Namespace: namespace,
 
 		Name:        name,
 		Date:        date,
 		Description: description,
 	})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		// Nothing was updated either because the activity doesn't exist or because it was changed in the meantime
+		if _, err := p.queries.GetActivityAndContact(ctx, models.GetActivityAndContactParams{
+			ID_2: id,
+
+			ID:        contactID,
+			Namespace: namespace,
+		}); err != nil {
+			return err
+		}
+
+		return ErrVersionConflict
+	}
+
+	return nil
 }
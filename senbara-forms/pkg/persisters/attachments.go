@@ -0,0 +1,231 @@
+package persisters
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strconv"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/tables"
+)
+
+var (
+	ErrJournalEntryDoesNotExist = errors.New("journal entry does not exist")
+)
+
+var (
+	attachmentReferenceRegexp = regexp.MustCompile(`attachment:([0-9]+)`)
+)
+
+// replaceAttachmentReferences rewrites the `attachment:ID` references in `body` to the
+// IDs that the attachments were recreated with, which `attachmentIDMap` maps old IDs to
+func replaceAttachmentReferences(body string, attachmentIDMap map[int32]int32) string {
+	return attachmentReferenceRegexp.ReplaceAllStringFunc(body, func(reference string) string {
+		id, err := strconv.ParseInt(attachmentReferenceRegexp.FindStringSubmatch(reference)[1], 10, 32)
+		if err != nil {
+			return reference
+		}
+
+		newID, ok := attachmentIDMap[int32(id)]
+		if !ok {
+			return reference
+		}
+
+		return "attachment:" + strconv.Itoa(int(newID))
+	})
+}
+
+// createAttachment stores `data` in the blob store and creates the attachment pointing to it,
+// returning the attachment's ID and its blob key. The blob is removed again if the attachment can't be created.
+func (p *Persister) createAttachment(
+	ctx context.Context,
+	qtx *tables.Queries,
+
+	name,
+	contentType string,
+	data []byte,
+
+	journalEntryID int32,
+	namespace string,
+) (int32, string, error) {
+	blobKey, err := p.blobs.Put(ctx, data)
+	if err != nil {
+		return 0, "", err
+	}
+
+	id, err := qtx.CreateAttachment(ctx, models.CreateAttachmentParams{
+		ID:          journalEntryID,
+		Namespace:   namespace,
+		Name:        name,
+		ContentType: contentType,
+		Size:        int32(len(data)),
+		BlobKey:     blobKey,
+	})
+	if err != nil {
+		_ = p.blobs.Delete(ctx, blobKey)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, "", ErrJournalEntryDoesNotExist
+		}
+
+		return 0, "", err
+	}
+
+	return id, blobKey, nil
+}
+
+// restoreAttachment recreates a trashed attachment with its original ID, so that references to it in the body of
+// the journal entry stay valid. The returned blob key has to be deleted by the caller if the transaction fails.
+func (p *Persister) restoreAttachment(
+	ctx context.Context,
+	qtx *tables.Queries,
+
+	attachment models.ExportedAttachment,
+
+	journalEntryID int32,
+	namespace string,
+) (string, error) {
+	blobKey, err := p.blobs.Put(ctx, attachment.Data)
+	if err != nil {
+		return "", err
+	}
+
+	rows, err := qtx.RestoreAttachment(ctx, models.RestoreAttachmentParams{
+		ID:             attachment.ID,
+		Name:           attachment.Name,
+		ContentType:    attachment.ContentType,
+		Size:           int32(len(attachment.Data)),
+		BlobKey:        blobKey,
+		JournalEntryID: journalEntryID,
+		Namespace:      namespace,
+	})
+	if err != nil {
+		_ = p.blobs.Delete(ctx, blobKey)
+
+		return "", err
+	}
+
+	if rows == 0 {
+		_ = p.blobs.Delete(ctx, blobKey)
+
+		return "", ErrJournalEntryDoesNotExist
+	}
+
+	return blobKey, nil
+}
+
+// deleteBlobs removes the blobs of attachments that have already been deleted from the database
+func (p *Persister) deleteBlobs(ctx context.Context, blobKeys []string) error {
+	errs := []error{}
+	for _, blobKey := range blobKeys {
+		if err := p.blobs.Delete(ctx, blobKey); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p *Persister) CreateAttachment(
+	ctx context.Context,
+
+	name,
+	contentType string,
+	data []byte,
+
+	journalEntryID int32,
+	namespace string,
+) (int32, error) {
+	id, _, err := p.createAttachment(ctx, p.queries, name, contentType, data, journalEntryID, namespace)
+
+	return id, err
+}
+
+func (p *Persister) GetAttachments(
+	ctx context.Context,
+
+	journalEntryID int32,
+	namespace string,
+) ([]models.Attachment, error) {
+	return p.queries.GetAttachments(ctx, models.GetAttachmentsParams{
+		JournalEntryID: journalEntryID,
+		Namespace:      namespace,
+	})
+}
+
+// GetAttachment returns an attachment together with its contents
+func (p *Persister) GetAttachment(
+	ctx context.Context,
+
+	id int32,
+	namespace string,
+) (models.Attachment, []byte, error) {
+	attachment, err := p.queries.GetAttachment(ctx, models.GetAttachmentParams{
+		ID:        id,
+		Namespace: namespace,
+	})
+	if err != nil {
+		return models.Attachment{}, nil, err
+	}
+
+	data, err := p.blobs.Get(ctx, attachment.BlobKey)
+	if err != nil {
+		return models.Attachment{}, nil, err
+	}
+
+	return attachment, data, nil
+}
+
+func (p *Persister) DeleteAttachment(
+	ctx context.Context,
+
+	id int32,
+	namespace string,
+) error {
+	blobKey, err := p.queries.DeleteAttachment(ctx, models.DeleteAttachmentParams{
+		ID:        id,
+		Namespace: namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.blobs.Delete(ctx, blobKey)
+}
+
+// getTrashedAttachments returns the attachments of a journal entry including their contents,
+// so that they can be stored in the trash together with the journal entry
+func (p *Persister) getTrashedAttachments(
+	ctx context.Context,
+	qtx *tables.Queries,
+
+	journalEntryID int32,
+	namespace string,
+) ([]models.ExportedAttachment, error) {
+	attachments, err := qtx.GetAttachments(ctx, models.GetAttachmentsParams{
+		JournalEntryID: journalEntryID,
+		Namespace:      namespace,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	trashedAttachments := []models.ExportedAttachment{}
+	for _, attachment := range attachments {
+		data, err := p.blobs.Get(ctx, attachment.BlobKey)
+		if err != nil {
+			return nil, err
+		}
+
+		trashedAttachments = append(trashedAttachments, models.ExportedAttachment{
+			ID:          attachment.ID,
+			Name:        attachment.Name,
+			ContentType: attachment.ContentType,
+			Data:        data,
+		})
+	}
+
+	return trashedAttachments, nil
+}
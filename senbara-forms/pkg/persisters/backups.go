@@ -0,0 +1,42 @@
+package persisters
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+)
+
+// GetNamespaces returns all namespaces that have any data
+func (p *Persister) GetNamespaces(ctx context.Context) ([]string, error) {
+	return p.queries.GetNamespaces(ctx)
+}
+
+func (p *Persister) GetBackupRun(ctx context.Context, namespace string) (models.BackupRun, error) {
+	return p.queries.GetBackupRun(ctx, namespace)
+}
+
+// UpdateBackupRun records the result of an automatic backup of a namespace. If `backupErr` is
+// set, the time of the last successful backup is kept. `journalSkipped` records whether the
+// journal was left out of the backup because it is encrypted.
+func (p *Persister) UpdateBackupRun(ctx context.Context, ranAt time.Time, fileName string, size int64, journalSkipped bool, backupErr error, namespace string) error {
+	params := models.UpdateBackupRunParams{
+		Namespace:      namespace,
+		RanAt:          ranAt,
+		FileName:       fileName,
+		Size:           size,
+		JournalSkipped: journalSkipped,
+	}
+
+	if backupErr != nil {
+		params.Error = backupErr.Error()
+	} else {
+		params.LastSucceededAt = sql.NullTime{
+			Time:  ranAt,
+			Valid: true,
+		}
+	}
+
+	return p.queries.UpdateBackupRun(ctx, params)
+}
@@ -0,0 +1,87 @@
+package persisters
+
+import (
+	"context"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+)
+
+// UpdateContactPhoto replaces all thumbnails of a contact's photo with `thumbnails`,
+// which maps each thumbnail size to its encoded image.
+func (p *Persister) UpdateContactPhoto(
+	ctx context.Context,
+
+	contentType string,
+	thumbnails map[int32][]byte,
+
+	contactID int32,
+	namespace string,
+) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := p.queries.WithTx(tx)
+
+	if err := qtx.DeleteContactPhotos(ctx, models.DeleteContactPhotosParams{
+		ContactID: contactID,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	for size, data := range thumbnails {
+		if _, err := qtx.CreateContactPhoto(ctx, models.CreateContactPhotoParams{
+			ID:          contactID,
+			Namespace:   namespace,
+			Size:        size,
+			ContentType: contentType,
+			Data:        data,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (p *Persister) GetContactPhoto(
+	ctx context.Context,
+
+	size int32,
+
+	contactID int32,
+	namespace string,
+) (models.GetContactPhotoRow, error) {
+	return p.queries.GetContactPhoto(ctx, models.GetContactPhotoParams{
+		ContactID: contactID,
+		Namespace: namespace,
+		Size:      size,
+	})
+}
+
+func (p *Persister) GetContactPhotoSizes(
+	ctx context.Context,
+
+	contactID int32,
+	namespace string,
+) ([]int32, error) {
+	return p.queries.GetContactPhotoSizes(ctx, models.GetContactPhotoSizesParams{
+		ContactID: contactID,
+		Namespace: namespace,
+	})
+}
+
+func (p *Persister) DeleteContactPhoto(
+	ctx context.Context,
+
+	contactID int32,
+	namespace string,
+) error {
+	return p.queries.DeleteContactPhotos(ctx, models.DeleteContactPhotosParams{
+		ContactID: contactID,
+		Namespace: namespace,
+	})
+}
@@ -3,15 +3,96 @@ package persisters
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"slices"
 	"time"
 
 	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
 )
 
+const (
+	ContactFrequencyWeekly    = "weekly"
+	ContactFrequencyMonthly   = "monthly"
+	ContactFrequencyQuarterly = "quarterly"
+
+	ContactsSortName      = "name"
+	ContactsSortUpdatedAt = "updatedAt"
+)
+
+var (
+	ErrInvalidContactFrequency      = errors.New("invalid contact frequency")
+	ErrCannotMergeContactWithItself = errors.New("contact can not be merged with itself")
+)
+
+var ContactFrequencies = []string{
+	ContactFrequencyWeekly,
+	ContactFrequencyMonthly,
+	ContactFrequencyQuarterly,
+}
+
 func (p *Persister) GetContacts(ctx context.Context, namespace string) ([]models.Contact, error) {
 	return p.queries.GetContacts(ctx, namespace)
 }
 
+// ContactsCursor is the position of a contact in a page. It carries the values that the contacts
+// are sorted by, so that pages can start after or end before it even if the contact has been deleted since.
+type ContactsCursor struct {
+	ID        int32
+	FirstName string
+	LastName  string
+	UpdatedAt time.Time
+}
+
+// GetContactsPage returns up to `limit` contacts, sorted by `sort` and starting
+// after `after`, or at the beginning if its ID is 0.
+func (p *Persister) GetContactsPage(
+	ctx context.Context,
+	sort string,
+	descending bool,
+	after ContactsCursor,
+	limit int32,
+	namespace string,
+) ([]models.Contact, error) {
+	switch {
+	case sort == ContactsSortName && !descending:
+		return p.queries.GetContactsByNameAsc(ctx, models.GetContactsByNameAscParams{
+			Namespace:      namespace,
+			AfterID:        after.ID,
+			AfterFirstName: after.FirstName,
+			AfterLastName:  after.LastName,
+			RowLimit:       limit,
+		})
+
+	case sort == ContactsSortName && descending:
+		return p.queries.GetContactsByNameDesc(ctx, models.GetContactsByNameDescParams{
+			Namespace:      namespace,
+			AfterID:        after.ID,
+			AfterFirstName: after.FirstName,
+			AfterLastName:  after.LastName,
+			RowLimit:       limit,
+		})
+
+	case sort == ContactsSortUpdatedAt && !descending:
+		return p.queries.GetContactsByUpdatedAtAsc(ctx, models.GetContactsByUpdatedAtAscParams{
+			Namespace:      namespace,
+			AfterID:        after.ID,
+			AfterUpdatedAt: after.UpdatedAt,
+			RowLimit:       limit,
+		})
+
+	case sort == ContactsSortUpdatedAt && descending:
+		return p.queries.GetContactsByUpdatedAtDesc(ctx, models.GetContactsByUpdatedAtDescParams{
+			Namespace:      namespace,
+			AfterID:        after.ID,
+			AfterUpdatedAt: after.UpdatedAt,
+			RowLimit:       limit,
+		})
+
+	default:
+		return nil, ErrInvalidSort
+	}
+}
+
 func (p *Persister) CreateContact(
 	ctx context.Context,
 	firstName string,
@@ -20,8 +101,17 @@ func (p *Persister) CreateContact(
 	email string,
 	pronouns string,
 	namespace string,
+	customFieldValues map[int32]string,
 ) (int32, error) {
-	return p.queries.CreateContact(ctx, models.CreateContactParams{
+	tx, err := p.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	qtx := p.queries.WithTx(tx)
+
+	id, err := qtx.CreateContact(ctx, models.CreateContactParams{
 		FirstName: firstName,
 		LastName:  lastName,
 		Nickname:  nickname,
@@ -29,6 +119,15 @@ func (p *Persister) CreateContact(
 		Pronouns:  pronouns,
 		Namespace: namespace,
 	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := setCustomFieldValues(ctx, qtx, customFieldValues, id, namespace); err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
 }
 
 func (p *Persister) GetContact(ctx context.Context, id int32, namespace string) (models.Contact, error) {
@@ -38,6 +137,23 @@ func (p *Persister) GetContact(ctx context.Context, id int32, namespace string)
 	})
 }
 
+// GetContactStatus returns when a contact was last contacted and whether getting in touch again is overdue,
+// which is computed the same way as for the dashboard
+func (p *Persister) GetContactStatus(ctx context.Context, id int32, namespace string) (models.ContactStatus, error) {
+	return p.queries.GetContactStatus(ctx, models.GetContactStatusParams{
+		ContactID: id,
+		Namespace: namespace,
+	})
+}
+
+// GetContactIDByName returns the ID of the oldest contact whose full name or nickname matches `name`, ignoring case
+func (p *Persister) GetContactIDByName(ctx context.Context, name, namespace string) (int32, error) {
+	return p.queries.GetContactIDByName(ctx, models.GetContactIDByNameParams{
+		Namespace: namespace,
+		Name:      name,
+	})
+}
+
 func (p *Persister) DeleteContact(ctx context.Context, id int32, namespace string) error {
 	tx, err := p.db.Begin()
 	if err != nil {
@@ -47,6 +163,122 @@ func (p *Persister) DeleteContact(ctx context.Context, id int32, namespace strin
 
 	qtx := p.queries.WithTx(tx)
 
+	contact, err := getExportedContact(ctx, qtx, id, namespace)
+	if err != nil {
+		return err
+	}
+
+	trashedContact := models.TrashedContact{
+		Contact: contact,
+	}
+
+	debts, err := qtx.GetDebts(ctx, models.GetDebtsParams{
+		ID:        id,
+		Namespace: namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, debt := range debts {
+		trashedContact.Debts = append(trashedContact.Debts, models.ExportedDebt{
+			ID:          debt.ID,
+			Amount:      debt.Amount,
+			Currency:    debt.Currency,
+			Description: debt.Description,
+		})
+	}
+
+	activities, err := qtx.GetActivities(ctx, models.GetActivitiesParams{
+		ID:        id,
+		Namespace: namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, activity := range activities {
+		trashedContact.Activities = append(trashedContact.Activities, models.ExportedActivity{
+			ID:          activity.ID,
+			Name:        activity.Name,
+			Date:        activity.Date,
+			Description: activity.Description,
+		})
+	}
+
+	relationships, err := qtx.GetRelationships(ctx, models.GetRelationshipsParams{
+		ID:        id,
+		Namespace: namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, relationship := range relationships {
+		trashedContact.Relationships = append(trashedContact.Relationships, models.TrashedRelationship{
+			RelatedContactID: relationship.RelatedContactID,
+			Kind:             relationship.Kind,
+		})
+	}
+
+	contactPhotos, err := qtx.GetContactPhotos(ctx, models.GetContactPhotosParams{
+		ContactID: id,
+		Namespace: namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, contactPhoto := range contactPhotos {
+		trashedContact.ContactPhotos = append(trashedContact.ContactPhotos, models.ExportedContactPhoto{
+			ID:          contactPhoto.ID,
+			Size:        contactPhoto.Size,
+			ContentType: contactPhoto.ContentType,
+			Data:        contactPhoto.Data,
+		})
+	}
+
+	mentioningJournalEntries, err := qtx.GetJournalEntriesMentioningContact(ctx, models.GetJournalEntriesMentioningContactParams{
+		ID:        id,
+		Namespace: namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, journalEntry := range mentioningJournalEntries {
+		trashedContact.MentioningJournalEntryIDs = append(trashedContact.MentioningJournalEntryIDs, journalEntry.ID)
+	}
+
+	if err := createTrashItem(
+		ctx,
+		qtx,
+
+		TrashEntityNameContact,
+		id,
+		contact.FirstName+" "+contact.LastName,
+		trashedContact,
+
+		namespace,
+	); err != nil {
+		return err
+	}
+
+	// Revisions are only deleted once the contact is removed from the trash for good
+	if err := qtx.DeleteRelationshipsForContact(ctx, models.DeleteRelationshipsForContactParams{
+		ID:        id,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteContactPhotos(ctx, models.DeleteContactPhotosParams{
+		ContactID: id,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
 	if err := qtx.DeleteDebtsForContact(ctx, models.DeleteDebtsForContactParams{
 		ID:        id,
 		Namespace: namespace,
@@ -54,6 +286,27 @@ func (p *Persister) DeleteContact(ctx context.Context, id int32, namespace strin
 		return err
 	}
 
+	if err := qtx.DeleteCustomFieldValuesForContact(ctx, models.DeleteCustomFieldValuesForContactParams{
+		ID:        id,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteActivitesForContact(ctx, models.DeleteActivitesForContactParams{
+		ID:        id,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteJournalEntryMentionsForContact(ctx, models.DeleteJournalEntryMentionsForContactParams{
+		ID:        id,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
 	if err := qtx.DeleteContact(ctx, models.DeleteContactParams{
 		ID:        id,
 		Namespace: namespace,
@@ -64,9 +317,12 @@ func (p *Persister) DeleteContact(ctx context.Context, id int32, namespace strin
 	return tx.Commit()
 }
 
+// UpdateContact updates a contact if it is still at `version` and stores its previous version as a revision.
+// If the contact was changed in the meantime, ErrVersionConflict is returned.
 func (p *Persister) UpdateContact(
 	ctx context.Context,
-	id int32,
+	id,
+	version int32,
 	firstName,
 	lastName,
 	nickname,
@@ -76,7 +332,13 @@ func (p *Persister) UpdateContact(
 	birthday *time.Time,
 	address,
 	notes string,
+	contactFrequency string,
+	customFieldValues map[int32]string,
 ) error {
+	if contactFrequency != "" && !slices.Contains(ContactFrequencies, contactFrequency) {
+		return ErrInvalidContactFrequency
+	}
+
 	var birthdayDate sql.NullTime
 	if birthday != nil {
 		birthdayDate = sql.NullTime{
@@ -85,7 +347,24 @@ func (p *Persister) UpdateContact(
 		}
 	}
 
-	return p.queries.UpdateContact(ctx, models.UpdateContactParams{
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := p.queries.WithTx(tx)
+
+	oldContact, err := getExportedContact(ctx, qtx, id, namespace)
+	if err != nil {
+		return err
+	}
+
+	if err := createRevision(ctx, qtx, RevisionEntityNameContact, id, oldContact, namespace); err != nil {
+		return err
+	}
+
+	rows, err := qtx.UpdateContact(ctx, models.UpdateContactParams{
 		ID:        id,
 		Namespace: namespace,
 		FirstName: firstName,
@@ -96,5 +375,214 @@ func (p *Persister) UpdateContact(
 		Birthday:  birthdayDate,
 		Address:   address,
 		Notes:     notes,
+
+		ContactFrequency: contactFrequency,
+
+		Version: sql.NullInt32{
+			Int32: version,
+			Valid: true,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrVersionConflict
+	}
+
+	if err := setCustomFieldValues(ctx, qtx, customFieldValues, id, namespace); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// MergeContacts updates the surviving contact with the merged field values, re-points all
+// debts, activities, relationships, photos, custom field values and journal entry mentions of the other contact to it and then deletes
+// the other contact, all in one transaction.
+func (p *Persister) MergeContacts(
+	ctx context.Context,
+
+	id,
+	otherID int32,
+	namespace string,
+
+	firstName,
+	lastName,
+	nickname,
+	email,
+	pronouns string,
+	birthday *time.Time,
+	address,
+	notes string,
+) error {
+	if id == otherID {
+		return ErrCannotMergeContactWithItself
+	}
+
+	var birthdayDate sql.NullTime
+	if birthday != nil {
+		birthdayDate = sql.NullTime{
+			Time:  *birthday,
+			Valid: true,
+		}
+	}
+
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := p.queries.WithTx(tx)
+
+	// Make sure that both contacts exist in the namespace before re-pointing anything to them
+	contact, err := qtx.GetContact(ctx, models.GetContactParams{
+		ID:        id,
+		Namespace: namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	otherContact, err := qtx.GetContact(ctx, models.GetContactParams{
+		ID:        otherID,
+		Namespace: namespace,
 	})
+	if err != nil {
+		return err
+	}
+
+	oldContact, err := getExportedContact(ctx, qtx, id, namespace)
+	if err != nil {
+		return err
+	}
+
+	if err := createRevision(ctx, qtx, RevisionEntityNameContact, id, oldContact, namespace); err != nil {
+		return err
+	}
+
+	contactFrequency := contact.ContactFrequency
+	if contactFrequency == "" {
+		contactFrequency = otherContact.ContactFrequency
+	}
+
+	if _, err := qtx.UpdateContact(ctx, models.UpdateContactParams{
+		ID:        id,
+		Namespace: namespace,
+		FirstName: firstName,
+		LastName:  lastName,
+		Nickname:  nickname,
+		Email:     email,
+		Pronouns:  pronouns,
+		Birthday:  birthdayDate,
+		Address:   address,
+		Notes:     notes,
+
+		ContactFrequency: contactFrequency,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.MoveDebtsToContact(ctx, models.MoveDebtsToContactParams{
+		ContactID: id,
+		ID:        otherID,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.MoveActivitiesToContact(ctx, models.MoveActivitiesToContactParams{
+		ContactID: id,
+		ID:        otherID,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteRelationshipsBetweenContacts(ctx, models.DeleteRelationshipsBetweenContactsParams{
+		ContactID:        id,
+		RelatedContactID: otherID,
+		Namespace:        namespace,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.CopyRelationshipsToContact(ctx, models.CopyRelationshipsToContactParams{
+		ID:        id,
+		ContactID: otherID,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteRelationshipsForContact(ctx, models.DeleteRelationshipsForContactParams{
+		ID:        otherID,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.MoveContactPhotosToContact(ctx, models.MoveContactPhotosToContactParams{
+		ContactID:   id,
+		ContactID_2: otherID,
+		Namespace:   namespace,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteContactPhotos(ctx, models.DeleteContactPhotosParams{
+		ContactID: otherID,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.MoveCustomFieldValuesToContact(ctx, models.MoveCustomFieldValuesToContactParams{
+		ContactID: id,
+		ID:        otherID,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteCustomFieldValuesForContact(ctx, models.DeleteCustomFieldValuesForContactParams{
+		ID:        otherID,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.MoveJournalEntryMentionsToContact(ctx, models.MoveJournalEntryMentionsToContactParams{
+		ID:        id,
+		ID_2:      otherID,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteJournalEntryMentionsForContact(ctx, models.DeleteJournalEntryMentionsForContactParams{
+		ID:        otherID,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteRevisionsForEntity(ctx, models.DeleteRevisionsForEntityParams{
+		Namespace:  namespace,
+		EntityName: RevisionEntityNameContact,
+		EntityID:   otherID,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteContact(ctx, models.DeleteContactParams{
+		ID:        otherID,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
@@ -0,0 +1,34 @@
+package persisters
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMergeContactsWithItself(t *testing.T) {
+	p, db := newFakePersister(t, nil)
+
+	if err := p.MergeContacts(
+		context.Background(),
+
+		1,
+		1,
+		"jane@example.com",
+
+		"Jane",
+		"Doe",
+		"",
+		"jane@example.com",
+		"she/her",
+		nil,
+		"",
+		"",
+	); !errors.Is(err, ErrCannotMergeContactWithItself) {
+		t.Fatalf("MergeContacts() error = %v, want %v", err, ErrCannotMergeContactWithItself)
+	}
+
+	if calls := db.calls("DeleteContact"); len(calls) != 0 {
+		t.Errorf("expected the contact to be kept, but it was deleted %v times", len(calls))
+	}
+}
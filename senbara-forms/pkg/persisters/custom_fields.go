@@ -0,0 +1,156 @@
+package persisters
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/tables"
+)
+
+const (
+	CustomFieldKindText   = "text"
+	CustomFieldKindNumber = "number"
+	CustomFieldKindDate   = "date"
+	CustomFieldKindURL    = "url"
+	CustomFieldKindSelect = "select"
+)
+
+var (
+	ErrInvalidCustomFieldKind = errors.New("invalid custom field kind")
+)
+
+var CustomFieldKinds = []string{
+	CustomFieldKindText,
+	CustomFieldKindNumber,
+	CustomFieldKindDate,
+	CustomFieldKindURL,
+	CustomFieldKindSelect,
+}
+
+func (p *Persister) CreateCustomField(
+	ctx context.Context,
+
+	name,
+	kind string,
+	options []string,
+
+	namespace string,
+) (int32, error) {
+	if !slices.Contains(CustomFieldKinds, kind) {
+		return 0, ErrInvalidCustomFieldKind
+	}
+
+	if options == nil {
+		options = []string{}
+	}
+
+	return p.queries.CreateCustomField(ctx, models.CreateCustomFieldParams{
+		Name:      name,
+		Kind:      kind,
+		Options:   options,
+		Namespace: namespace,
+	})
+}
+
+func (p *Persister) GetCustomFields(ctx context.Context, namespace string) ([]models.CustomField, error) {
+	return p.queries.GetCustomFields(ctx, namespace)
+}
+
+func (p *Persister) DeleteCustomField(ctx context.Context, id int32, namespace string) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := p.queries.WithTx(tx)
+
+	if err := qtx.DeleteCustomFieldValuesForCustomField(ctx, models.DeleteCustomFieldValuesForCustomFieldParams{
+		ID:        id,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteCustomField(ctx, models.DeleteCustomFieldParams{
+		ID:        id,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (p *Persister) GetCustomFieldValues(ctx context.Context, contactID int32, namespace string) ([]models.GetCustomFieldValuesRow, error) {
+	return p.queries.GetCustomFieldValues(ctx, models.GetCustomFieldValuesParams{
+		ID:        contactID,
+		Namespace: namespace,
+	})
+}
+
+// setCustomFieldValues replaces all custom field values of a contact; empty values are dropped.
+func setCustomFieldValues(
+	ctx context.Context,
+	qtx *tables.Queries,
+
+	customFieldValues map[int32]string,
+
+	contactID int32,
+	namespace string,
+) error {
+	if err := qtx.DeleteCustomFieldValuesForContact(ctx, models.DeleteCustomFieldValuesForContactParams{
+		ID:        contactID,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	for customFieldID, value := range customFieldValues {
+		if value == "" {
+			continue
+		}
+
+		if _, err := qtx.CreateCustomFieldValue(ctx, models.CreateCustomFieldValueParams{
+			ID:        contactID,
+			ID_2:      customFieldID,
+			Value:     value,
+			Namespace: namespace,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setCustomFieldValuesByName is like setCustomFieldValues, but references custom fields by
+// name and creates text fields for names that don't exist in the namespace yet.
+func setCustomFieldValuesByName(
+	ctx context.Context,
+	qtx *tables.Queries,
+
+	customFieldValues map[string]string,
+
+	contactID int32,
+	namespace string,
+) error {
+	customFieldValuesByID := map[int32]string{}
+	for name, value := range customFieldValues {
+		customFieldID, err := qtx.EnsureCustomField(ctx, models.EnsureCustomFieldParams{
+			Name:      name,
+			Kind:      CustomFieldKindText,
+			Options:   []string{},
+			Namespace: namespace,
+		})
+		if err != nil {
+			return err
+		}
+
+		customFieldValuesByID[customFieldID] = value
+	}
+
+	return setCustomFieldValues(ctx, qtx, customFieldValuesByID, contactID, namespace)
+}
@@ -0,0 +1,85 @@
+package persisters
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+)
+
+// GetDashboard collects the data for the home page in one read-only transaction, so that all
+// aggregates are based on the same snapshot. `limit` caps the length of each of the lists.
+// The titles of encrypted journal entries are decrypted with `key`, or empty if the journal is locked.
+// Upcoming birthdays are those within `birthdayDays` days from `today`, including `today`.
+func (p *Persister) GetDashboard(ctx context.Context, key []byte, today time.Time, birthdayDays, limit int32, namespace string) (models.Dashboard, error) {
+	tx, err := p.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return models.Dashboard{}, err
+	}
+	defer tx.Rollback()
+
+	qtx := p.queries.WithTx(tx)
+
+	c, err := getJournalCipher(ctx, qtx, key, namespace)
+	if err != nil {
+		return models.Dashboard{}, err
+	}
+
+	var dashboard models.Dashboard
+
+	dashboard.RecentJournalEntries, err = qtx.GetRecentJournalEntries(ctx, models.GetRecentJournalEntriesParams{
+		Namespace: namespace,
+		Limit:     limit,
+	})
+	if err != nil {
+		return models.Dashboard{}, err
+	}
+
+	for i, journalEntry := range dashboard.RecentJournalEntries {
+		if dashboard.RecentJournalEntries[i].Title, err = c.openTitle(journalEntry.Title); err != nil {
+			return models.Dashboard{}, err
+		}
+	}
+
+	dashboard.DebtBalances, err = qtx.GetDebtBalances(ctx, namespace)
+	if err != nil {
+		return models.Dashboard{}, err
+	}
+
+	dashboard.UpcomingActivities, err = qtx.GetUpcomingActivities(ctx, models.GetUpcomingActivitiesParams{
+		Namespace: namespace,
+		Limit:     limit,
+	})
+	if err != nil {
+		return models.Dashboard{}, err
+	}
+
+	dashboard.RecentlyUpdatedContacts, err = qtx.GetRecentlyUpdatedContacts(ctx, models.GetRecentlyUpdatedContactsParams{
+		Namespace: namespace,
+		Limit:     limit,
+	})
+	if err != nil {
+		return models.Dashboard{}, err
+	}
+
+	dashboard.OverdueContacts, err = qtx.GetOverdueContacts(ctx, namespace)
+	if err != nil {
+		return models.Dashboard{}, err
+	}
+
+	dashboard.UpcomingBirthdays, err = qtx.GetUpcomingBirthdays(ctx, models.GetUpcomingBirthdaysParams{
+		Namespace: namespace,
+		Today:     today,
+		Days:      birthdayDays,
+		RowLimit:  limit,
+	})
+	if err != nil {
+		return models.Dashboard{}, err
+	}
+
+	return dashboard, tx.Commit()
+}
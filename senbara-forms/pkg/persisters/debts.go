@@ -2,6 +2,7 @@ package persisters
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
 )
@@ -45,12 +46,57 @@ func (p *Persister) SettleDebt(
 	contactID int32,
 	namespace string,
 ) error {
-	return p.queries.SettleDebt(ctx, models.SettleDebtParams{
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := p.queries.WithTx(tx)
+
+	debt, err := qtx.GetDebtAndContact(ctx, models.GetDebtAndContactParams{
 		ID_2: id,
 
 		ID:        contactID,
 		Namespace: namespace,
 	})
+	if err != nil {
+		return err
+	}
+
+	if err := createTrashItem(
+		ctx,
+		qtx,
+
+		TrashEntityNameDebt,
+		debt.DebtID,
+		getDebtTitle(debt.Amount, debt.Currency, debt.Description),
+		models.ExportedDebt{
+			ID:          debt.DebtID,
+			Amount:      debt.Amount,
+			Currency:    debt.Currency,
+			Description: debt.Description,
+			ContactID: sql.NullInt32{
+				Int32: debt.ContactID,
+				Valid: true,
+			},
+		},
+
+		namespace,
+	); err != nil {
+		return err
+	}
+
+	if err := qtx.SettleDebt(ctx, models.SettleDebtParams{
+		ID_2: id,
+
+		ID:        contactID,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (p *Persister) GetDebtAndContact(
@@ -72,7 +118,8 @@ func (p *Persister) GetDebtAndContact(
 func (p *Persister) UpdateDebt(
 	ctx context.Context,
 
-	id int32,
+	id,
+	version int32,
 
 	contactID int32,
 	namespace string,
@@ -81,14 +128,34 @@ func (p *Persister) UpdateDebt(
 	currency,
 	description string,
 ) error {
-	return p.queries.UpdateDebt(ctx, models.UpdateDebtParams{
-		ID_2: id,
+	rows, err := p.queries.UpdateDebt(ctx, models.UpdateDebtParams{
+		ID:      id,
+		Version: version,
 
-		ID:        contactID,
+		ContactID: contactID,
 		Namespace: namespace,
 
 		Amount:      amount,
 		Currency:    currency,
 		Description: description,
 	})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		// Nothing was updated either because the debt doesn't exist or because it was changed in the meantime
+		if _, err := p.queries.GetDebtAndContact(ctx, models.GetDebtAndContactParams{
+			ID_2: id,
+
+			ID:        contactID,
+			Namespace: namespace,
+		}); err != nil {
+			return err
+		}
+
+		return ErrVersionConflict
+	}
+
+	return nil
 }
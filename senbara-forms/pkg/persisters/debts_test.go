@@ -0,0 +1,39 @@
+package persisters
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestUpdateDebtWithoutAffectedRows(t *testing.T) {
+	tests := []struct {
+		name   string
+		exists bool
+		err    error
+	}{
+		{"missing debt", false, sql.ErrNoRows},
+		{"changed debt", true, ErrVersionConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := newFakePersister(t, func(name string, args []driver.Value) (fakeResult, bool, error) {
+				if name != "GetDebtAndContact" || !tt.exists {
+					return fakeResult{}, false, nil
+				}
+
+				return fakeResult{
+					columns: []string{"debt_id", "amount", "currency", "description", "version", "contact_id", "first_name", "last_name"},
+					rows:    [][]driver.Value{{int64(1), 10.0, "EUR", "", int64(2), int64(1), "Jane", "Doe"}},
+				}, true, nil
+			})
+
+			if err := p.UpdateDebt(context.Background(), 1, 1, 1, "jane@example.com", 10, "EUR", ""); !errors.Is(err, tt.err) {
+				t.Errorf("UpdateDebt() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package persisters
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/tables"
+)
+
+var (
+	fakeQueryNameRegexp = regexp.MustCompile(`^-- name: (\w+) :(\w+)`)
+)
+
+type fakeQuery struct {
+	name string
+	args []driver.Value
+
+	// id is the ID that a query creating a row returned
+	id int64
+}
+
+// fakeResult is the result of a query. For statements that don't return rows,
+// the number of rows is used as the number of affected rows.
+type fakeResult struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+// fakeDB is a database that records the queries that the persister runs and answers them
+// with `respond`, so that persisters can be tested without Postgres. Queries that `respond`
+// doesn't handle are answered like Postgres would for an empty namespace, except that
+// queries creating a row return a new ID.
+type fakeDB struct {
+	lock    sync.Mutex
+	queries []fakeQuery
+	nextID  int64
+
+	respond func(name string, args []driver.Value) (fakeResult, bool, error)
+}
+
+func newFakePersister(t *testing.T, respond func(name string, args []driver.Value) (fakeResult, bool, error)) (*Persister, *fakeDB) {
+	t.Helper()
+
+	f := &fakeDB{
+		nextID:  100,
+		respond: respond,
+	}
+
+	db := sql.OpenDB(fakeConnector{f})
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	return &Persister{
+		db:      db,
+		queries: tables.New(db),
+	}, f
+}
+
+// calls returns the queries with `name` in the order they were run
+func (f *fakeDB) calls(name string) []fakeQuery {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	calls := []fakeQuery{}
+	for _, query := range f.queries {
+		if query.name == name {
+			calls = append(calls, query)
+		}
+	}
+
+	return calls
+}
+
+func (f *fakeDB) run(query string, args []driver.Value) (fakeResult, error) {
+	name, kind := "", ""
+	if match := fakeQueryNameRegexp.FindStringSubmatch(query); match != nil {
+		name, kind = match[1], match[2]
+	}
+
+	call := fakeQuery{
+		name: name,
+		args: args,
+	}
+
+	if f.respond != nil {
+		if res, ok, err := f.respond(name, args); ok || err != nil {
+			f.lock.Lock()
+			defer f.lock.Unlock()
+
+			f.queries = append(f.queries, call)
+
+			return res, err
+		}
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	res := fakeResult{}
+	if kind == "one" && strings.HasPrefix(name, "Create") {
+		f.nextID++
+
+		call.id = f.nextID
+		res = fakeResult{
+			columns: []string{"id"},
+			rows:    [][]driver.Value{{f.nextID}},
+		}
+	}
+
+	f.queries = append(f.queries, call)
+
+	return res, nil
+}
+
+type fakeConnector struct {
+	db *fakeDB
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return fakeConn{c.db}, nil
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("fake database can only be opened with a connector")
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return fakeStmt{c.db, query}, nil
+}
+
+func (c fakeConn) Close() error {
+	return nil
+}
+
+func (c fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error {
+	return nil
+}
+
+func (fakeTx) Rollback() error {
+	return nil
+}
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s fakeStmt) Close() error {
+	return nil
+}
+
+func (s fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	res, err := s.db.run(s.query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.RowsAffected(len(res.rows)), nil
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	res, err := s.db.run(s.query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fakeRows{res: res}, nil
+}
+
+type fakeRows struct {
+	res fakeResult
+	i   int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.res.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.i >= len(r.res.rows) {
+		return io.EOF
+	}
+
+	copy(dest, r.res.rows[r.i])
+	r.i++
+
+	return nil
+}
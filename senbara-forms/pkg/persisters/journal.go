@@ -2,43 +2,407 @@ package persisters
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/tables"
 )
 
-func (p *Persister) GetJournalEntries(ctx context.Context, namespace string) ([]models.JournalEntry, error) {
-	return p.queries.GetJournalEntries(ctx, namespace)
+const (
+	JournalEntriesSortDate   = "date"
+	JournalEntriesSortRating = "rating"
+)
+
+// JournalEntriesCursor is the position of a journal entry in a page. It carries the values that the entries
+// are sorted by, so that pages can start after or end before it even if the entry has been deleted since.
+type JournalEntriesCursor struct {
+	ID     int32
+	Date   time.Time
+	Rating int32
+}
+
+// GetJournalEntriesPage returns up to `limit` journal entries with all of the `tags`, sorted by `sort` and
+// starting after `after`, or at the beginning if its ID is 0.
+func (p *Persister) GetJournalEntriesPage(
+	ctx context.Context,
+	key []byte,
+	sort string,
+	descending bool,
+	tags []string,
+	after JournalEntriesCursor,
+	limit int32,
+	namespace string,
+) ([]models.JournalEntry, error) {
+	c, err := getJournalCipher(ctx, p.queries, key, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var journalEntries []models.JournalEntry
+	switch {
+	case sort == JournalEntriesSortDate && !descending:
+		journalEntries, err = p.queries.GetJournalEntriesByDateAsc(ctx, models.GetJournalEntriesByDateAscParams{
+			Namespace: namespace,
+			Tags:      tags,
+			AfterID:   after.ID,
+			AfterDate: after.Date,
+			RowLimit:  limit,
+		})
+
+	case sort == JournalEntriesSortDate && descending:
+		journalEntries, err = p.queries.GetJournalEntriesByDateDesc(ctx, models.GetJournalEntriesByDateDescParams{
+			Namespace: namespace,
+			Tags:      tags,
+			AfterID:   after.ID,
+			AfterDate: after.Date,
+			RowLimit:  limit,
+		})
+
+	case sort == JournalEntriesSortRating && !descending:
+		journalEntries, err = p.queries.GetJournalEntriesByRatingAsc(ctx, models.GetJournalEntriesByRatingAscParams{
+			Namespace:   namespace,
+			Tags:        tags,
+			AfterID:     after.ID,
+			AfterRating: after.Rating,
+			RowLimit:    limit,
+		})
+
+	case sort == JournalEntriesSortRating && descending:
+		journalEntries, err = p.queries.GetJournalEntriesByRatingDesc(ctx, models.GetJournalEntriesByRatingDescParams{
+			Namespace:   namespace,
+			Tags:        tags,
+			AfterID:     after.ID,
+			AfterRating: after.Rating,
+			RowLimit:    limit,
+		})
+
+	default:
+		return nil, ErrInvalidSort
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, journalEntry := range journalEntries {
+		if journalEntries[i].Title, journalEntries[i].Body, err = c.openJournalEntry(journalEntry.Title, journalEntry.Body); err != nil {
+			return nil, err
+		}
+	}
+
+	return journalEntries, nil
+}
+
+// GetJournalEntries returns all journal entries of a namespace with their titles and bodies decrypted, newest first
+func (p *Persister) GetJournalEntries(ctx context.Context, key []byte, namespace string) ([]models.JournalEntry, error) {
+	c, err := getJournalCipher(ctx, p.queries, key, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	journalEntries, err := p.queries.GetJournalEntries(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, journalEntry := range journalEntries {
+		if journalEntries[i].Title, journalEntries[i].Body, err = c.openJournalEntry(journalEntry.Title, journalEntry.Body); err != nil {
+			return nil, err
+		}
+	}
+
+	return journalEntries, nil
+}
+
+// CreateJournalEntry creates a journal entry. The `date` is stored in UTC, and the `title` and
+// `body` are encrypted with `key` if journal encryption is enabled.
+func (p *Persister) CreateJournalEntry(ctx context.Context, key []byte, title, body string, rating int32, date time.Time, tags []string, namespace string) (int32, error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	qtx := p.queries.WithTx(tx)
+
+	c, err := getJournalCipher(ctx, qtx, key, namespace)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := createJournalEntry(ctx, qtx, c, title, body, rating, date, tags, namespace)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
 }
 
-func (p *Persister) CreateJournalEntry(ctx context.Context, title, body string, rating int32, namespace string) (int32, error) {
-	return p.queries.CreateJournalEntry(ctx, models.CreateJournalEntryParams{
-		Title:     title,
-		Body:      body,
+// CreateJournalEntries creates multiple journal entries like CreateJournalEntry. Either all of them are created or none.
+func (p *Persister) CreateJournalEntries(ctx context.Context, key []byte, journalEntries []models.ExportedJournalEntry, namespace string) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := p.queries.WithTx(tx)
+
+	c, err := getJournalCipher(ctx, qtx, key, namespace)
+	if err != nil {
+		return err
+	}
+
+	for _, journalEntry := range journalEntries {
+		if _, err := createJournalEntry(
+			ctx,
+			qtx,
+			c,
+
+			journalEntry.Title,
+			journalEntry.Body,
+			journalEntry.Rating,
+			journalEntry.Date,
+			journalEntry.Tags,
+
+			namespace,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func createJournalEntry(
+	ctx context.Context,
+	qtx *tables.Queries,
+	c journalCipher,
+
+	title,
+	body string,
+	rating int32,
+	date time.Time,
+	tags []string,
+
+	namespace string,
+) (int32, error) {
+	sealedTitle, sealedBody, err := c.sealJournalEntry(title, body)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := qtx.CreateJournalEntry(ctx, models.CreateJournalEntryParams{
+		Title:     sealedTitle,
+		Date:      date.UTC(),
+		Body:      sealedBody,
 		Rating:    rating,
 		Namespace: namespace,
 	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := setJournalEntryTags(ctx, qtx, tags, id, namespace); err != nil {
+		return 0, err
+	}
+
+	if err := setJournalEntryMentions(ctx, qtx, body, id, namespace); err != nil {
+		return 0, err
+	}
+
+	return id, nil
 }
 
 func (p *Persister) DeleteJournalEntry(ctx context.Context, id int32, namespace string) error {
-	return p.queries.DeleteJournalEntry(ctx, models.DeleteJournalEntryParams{
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := p.queries.WithTx(tx)
+
+	journalEntry, err := getExportedJournalEntry(ctx, qtx, id, namespace)
+	if err != nil {
+		return err
+	}
+
+	attachments, err := p.getTrashedAttachments(ctx, qtx, id, namespace)
+	if err != nil {
+		return err
+	}
+
+	if err := createTrashItem(
+		ctx,
+		qtx,
+
+		TrashEntityNameJournalEntry,
+		id,
+		journalEntry.Title,
+		models.TrashedJournalEntry{
+			ExportedJournalEntry: journalEntry,
+			Attachments:          attachments,
+		},
+
+		namespace,
+	); err != nil {
+		return err
+	}
+
+	blobKeys, err := qtx.DeleteAttachmentsForJournalEntry(ctx, models.DeleteAttachmentsForJournalEntryParams{
+		JournalEntryID: id,
+		Namespace:      namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Revisions are only deleted once the journal entry is removed from the trash for good
+	if err := qtx.DeleteJournalEntryTags(ctx, models.DeleteJournalEntryTagsParams{
 		ID:        id,
 		Namespace: namespace,
-	})
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteJournalEntryMentions(ctx, models.DeleteJournalEntryMentionsParams{
+		ID:        id,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteJournalEntry(ctx, models.DeleteJournalEntryParams{
+		ID:        id,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// The attachments' contents are kept in the trash item, so their blobs are no longer needed
+	return p.deleteBlobs(ctx, blobKeys)
+}
+
+// GetJournalEntryRatings returns the dates and ratings of all journal entries, oldest first
+func (p *Persister) GetJournalEntryRatings(ctx context.Context, namespace string) ([]models.GetJournalEntryRatingsRow, error) {
+	return p.queries.GetJournalEntryRatings(ctx, namespace)
 }
 
-func (p *Persister) GetJournalEntry(ctx context.Context, id int32, namespace string) (models.JournalEntry, error) {
-	return p.queries.GetJournalEntry(ctx, models.GetJournalEntryParams{
+func (p *Persister) GetJournalEntry(ctx context.Context, key []byte, id int32, namespace string) (models.JournalEntry, error) {
+	c, err := getJournalCipher(ctx, p.queries, key, namespace)
+	if err != nil {
+		return models.JournalEntry{}, err
+	}
+
+	journalEntry, err := p.queries.GetJournalEntry(ctx, models.GetJournalEntryParams{
 		ID:        id,
 		Namespace: namespace,
 	})
+	if err != nil {
+		return models.JournalEntry{}, err
+	}
+
+	if journalEntry.Title, journalEntry.Body, err = c.openJournalEntry(journalEntry.Title, journalEntry.Body); err != nil {
+		return models.JournalEntry{}, err
+	}
+
+	return journalEntry, nil
+}
+
+// UpdateJournalEntry updates a journal entry if it is still at `version` and stores its previous version as a revision.
+// If the entry was changed in the meantime, ErrVersionConflict is returned. The `date` is stored in UTC.
+func (p *Persister) UpdateJournalEntry(ctx context.Context, key []byte, id, version int32, title, body string, rating int32, date time.Time, tags []string, namespace string) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := p.queries.WithTx(tx)
+
+	c, err := getJournalCipher(ctx, qtx, key, namespace)
+	if err != nil {
+		return err
+	}
+
+	if err := updateJournalEntry(
+		ctx,
+		qtx,
+		c,
+
+		id,
+		sql.NullInt32{
+			Int32: version,
+			Valid: true,
+		},
+		title,
+		body,
+		rating,
+		date,
+		tags,
+
+		namespace,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (p *Persister) UpdateJournalEntry(ctx context.Context, id int32, title, body string, rating int32, namespace string) error {
-	return p.queries.UpdateJournalEntry(ctx, models.UpdateJournalEntryParams{
+func updateJournalEntry(
+	ctx context.Context,
+	qtx *tables.Queries,
+	c journalCipher,
+
+	id int32,
+	version sql.NullInt32,
+	title,
+	body string,
+	rating int32,
+	date time.Time,
+	tags []string,
+
+	namespace string,
+) error {
+	oldJournalEntry, err := getExportedJournalEntry(ctx, qtx, id, namespace)
+	if err != nil {
+		return err
+	}
+
+	if err := createRevision(ctx, qtx, RevisionEntityNameJournalEntry, id, oldJournalEntry, namespace); err != nil {
+		return err
+	}
+
+	sealedTitle, sealedBody, err := c.sealJournalEntry(title, body)
+	if err != nil {
+		return err
+	}
+
+	rows, err := qtx.UpdateJournalEntry(ctx, models.UpdateJournalEntryParams{
 		ID:        id,
 		Namespace: namespace,
-		Title:     title,
-		Body:      body,
+		Title:     sealedTitle,
+		Body:      sealedBody,
 		Rating:    rating,
+		Date:      date.UTC(),
+		Version:   version,
 	})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrVersionConflict
+	}
+
+	if err := setJournalEntryTags(ctx, qtx, tags, id, namespace); err != nil {
+		return err
+	}
+
+	return setJournalEntryMentions(ctx, qtx, body, id, namespace)
 }
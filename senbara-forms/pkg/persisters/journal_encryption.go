@@ -0,0 +1,386 @@
+package persisters
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/encryption"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/tables"
+)
+
+const (
+	journalEncryptionFieldTitle = "title"
+	journalEncryptionFieldBody  = "body"
+	journalEncryptionFieldCheck = "check"
+
+	// journalEncryptionCheckPlaintext is encrypted when encryption is enabled so that passphrases can be verified later on
+	journalEncryptionCheckPlaintext = "senbara-forms"
+)
+
+var (
+	ErrJournalLocked             = errors.New("journal is encrypted and locked")
+	ErrJournalEncryptionEnabled  = errors.New("journal encryption is already enabled")
+	ErrJournalEncryptionDisabled = errors.New("journal encryption is not enabled")
+	ErrInvalidPassphrase         = errors.New("invalid passphrase")
+)
+
+// journalCipher seals and opens the titles and bodies of the journal entries of a namespace.
+// If journal encryption isn't enabled for the namespace, they are passed through as they are.
+type journalCipher struct {
+	enabled   bool
+	key       []byte
+	namespace string
+}
+
+// getJournalCipher returns the cipher for a namespace. If `key` is nil or doesn't belong to the current
+// passphrase, e.g. because encryption was re-enabled in the meantime, the cipher is locked.
+func getJournalCipher(ctx context.Context, qtx *tables.Queries, key []byte, namespace string) (journalCipher, error) {
+	settings, err := qtx.GetSettings(ctx, namespace)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return journalCipher{namespace: namespace}, nil
+		}
+
+		return journalCipher{}, err
+	}
+
+	if !settings.JournalEncrypted {
+		return journalCipher{namespace: namespace}, nil
+	}
+
+	c := journalCipher{
+		enabled:   true,
+		namespace: namespace,
+	}
+
+	if key != nil {
+		if check, err := encryption.Decrypt(key, settings.JournalEncryptionCheck, c.getAdditionalData(journalEncryptionFieldCheck)); err == nil && check == journalEncryptionCheckPlaintext {
+			c.key = key
+		}
+	}
+
+	return c, nil
+}
+
+func (c journalCipher) locked() bool {
+	return c.enabled && c.key == nil
+}
+
+// getAdditionalData binds a ciphertext to the namespace and field it was created for. It isn't bound to a
+// journal entry, so someone with write access to the database can still swap the titles or bodies of two
+// entries, or replace them with older ones from revisions or the trash, without this being detected.
+func (c journalCipher) getAdditionalData(field string) string {
+	return c.namespace + "/" + field
+}
+
+func (c journalCipher) seal(field, plaintext string) (string, error) {
+	if !c.enabled {
+		return plaintext, nil
+	}
+
+	if c.key == nil {
+		return "", ErrJournalLocked
+	}
+
+	return encryption.Encrypt(c.key, plaintext, c.getAdditionalData(field))
+}
+
+func (c journalCipher) open(field, ciphertext string) (string, error) {
+	if !c.enabled {
+		return ciphertext, nil
+	}
+
+	if c.key == nil {
+		return "", ErrJournalLocked
+	}
+
+	return encryption.Decrypt(c.key, ciphertext, c.getAdditionalData(field))
+}
+
+func (c journalCipher) sealJournalEntry(title, body string) (string, string, error) {
+	sealedTitle, err := c.seal(journalEncryptionFieldTitle, title)
+	if err != nil {
+		return "", "", err
+	}
+
+	sealedBody, err := c.seal(journalEncryptionFieldBody, body)
+	if err != nil {
+		return "", "", err
+	}
+
+	return sealedTitle, sealedBody, nil
+}
+
+func (c journalCipher) openJournalEntry(title, body string) (string, string, error) {
+	openedTitle, err := c.open(journalEncryptionFieldTitle, title)
+	if err != nil {
+		return "", "", err
+	}
+
+	openedBody, err := c.open(journalEncryptionFieldBody, body)
+	if err != nil {
+		return "", "", err
+	}
+
+	return openedTitle, openedBody, nil
+}
+
+// openTitle opens the title of a journal entry for lists that are also shown while the journal is locked,
+// in which case an empty title is returned
+func (c journalCipher) openTitle(title string) (string, error) {
+	if c.locked() {
+		return "", nil
+	}
+
+	return c.open(journalEncryptionFieldTitle, title)
+}
+
+// IsJournalUnlocked reports whether `key` can be used to read and write the journal of a namespace.
+// This is always the case if journal encryption isn't enabled.
+func (p *Persister) IsJournalUnlocked(ctx context.Context, key []byte, namespace string) (bool, error) {
+	c, err := getJournalCipher(ctx, p.queries, key, namespace)
+	if err != nil {
+		return false, err
+	}
+
+	return !c.locked(), nil
+}
+
+// UnlockJournal derives the key for the encrypted journal of a namespace from its passphrase
+func (p *Persister) UnlockJournal(ctx context.Context, passphrase, namespace string) ([]byte, error) {
+	settings, err := p.GetSettings(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if !settings.JournalEncrypted {
+		return nil, ErrJournalEncryptionDisabled
+	}
+
+	key := encryption.DeriveKey(passphrase, settings.JournalEncryptionSalt)
+
+	c, err := getJournalCipher(ctx, p.queries, key, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.locked() {
+		return nil, ErrInvalidPassphrase
+	}
+
+	return key, nil
+}
+
+// EnableJournalEncryption encrypts the titles and bodies of all journal entries of a namespace, including
+// the ones in their revisions and in the trash, with a key derived from `passphrase`, which is returned.
+// Journal entries that are encrypted are excluded from the full-text search index.
+func (p *Persister) EnableJournalEncryption(ctx context.Context, passphrase, namespace string) ([]byte, error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	qtx := p.queries.WithTx(tx)
+
+	c, err := getJournalCipher(ctx, qtx, nil, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.enabled {
+		return nil, ErrJournalEncryptionEnabled
+	}
+
+	salt, err := encryption.NewSalt()
+	if err != nil {
+		return nil, err
+	}
+
+	c = journalCipher{
+		enabled:   true,
+		key:       encryption.DeriveKey(passphrase, salt),
+		namespace: namespace,
+	}
+
+	check, err := c.seal(journalEncryptionFieldCheck, journalEncryptionCheckPlaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	// Settings have to be updated first so that the search vectors of the encrypted journal entries are cleared
+	if err := qtx.UpdateJournalEncryption(ctx, models.UpdateJournalEncryptionParams{
+		Namespace:              namespace,
+		JournalEncrypted:       true,
+		JournalEncryptionSalt:  salt,
+		JournalEncryptionCheck: check,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := convertJournal(ctx, qtx, c.seal, namespace); err != nil {
+		return nil, err
+	}
+
+	return c.key, tx.Commit()
+}
+
+// DisableJournalEncryption decrypts all journal entries of a namespace again, which requires the journal to be unlocked
+func (p *Persister) DisableJournalEncryption(ctx context.Context, key []byte, namespace string) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := p.queries.WithTx(tx)
+
+	c, err := getJournalCipher(ctx, qtx, key, namespace)
+	if err != nil {
+		return err
+	}
+
+	if !c.enabled {
+		return ErrJournalEncryptionDisabled
+	}
+
+	if c.locked() {
+		return ErrJournalLocked
+	}
+
+	// Settings have to be updated first so that the search vectors are re-computed from the decrypted journal entries
+	if err := qtx.UpdateJournalEncryption(ctx, models.UpdateJournalEncryptionParams{
+		Namespace:              namespace,
+		JournalEncrypted:       false,
+		JournalEncryptionSalt:  []byte{},
+		JournalEncryptionCheck: "",
+	}); err != nil {
+		return err
+	}
+
+	if err := convertJournal(ctx, qtx, c.open, namespace); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// convertJournal applies `convert` to the titles and bodies of all journal entries
+// of a namespace, including the copies of them in revisions and trash items
+func convertJournal(
+	ctx context.Context,
+	qtx *tables.Queries,
+
+	convert func(field, value string) (string, error),
+
+	namespace string,
+) error {
+	journalEntries, err := qtx.GetJournalEntries(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	for _, journalEntry := range journalEntries {
+		title, err := convert(journalEncryptionFieldTitle, journalEntry.Title)
+		if err != nil {
+			return err
+		}
+
+		body, err := convert(journalEncryptionFieldBody, journalEntry.Body)
+		if err != nil {
+			return err
+		}
+
+		if err := qtx.UpdateJournalEntryTitleAndBody(ctx, models.UpdateJournalEntryTitleAndBodyParams{
+			ID:        journalEntry.ID,
+			Namespace: namespace,
+			Title:     title,
+			Body:      body,
+		}); err != nil {
+			return err
+		}
+	}
+
+	revisions, err := qtx.GetRevisionsForEntityName(ctx, models.GetRevisionsForEntityNameParams{
+		Namespace:  namespace,
+		EntityName: RevisionEntityNameJournalEntry,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, revision := range revisions {
+		var journalEntry models.ExportedJournalEntry
+		if err := json.Unmarshal(revision.Data, &journalEntry); err != nil {
+			return err
+		}
+
+		if journalEntry.Title, err = convert(journalEncryptionFieldTitle, journalEntry.Title); err != nil {
+			return err
+		}
+
+		if journalEntry.Body, err = convert(journalEncryptionFieldBody, journalEntry.Body); err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(journalEntry)
+		if err != nil {
+			return err
+		}
+
+		if err := qtx.UpdateRevisionData(ctx, models.UpdateRevisionDataParams{
+			ID:        revision.ID,
+			Namespace: namespace,
+			Data:      data,
+		}); err != nil {
+			return err
+		}
+	}
+
+	trashItems, err := qtx.GetTrashItemsForEntityName(ctx, models.GetTrashItemsForEntityNameParams{
+		Namespace:  namespace,
+		EntityName: TrashEntityNameJournalEntry,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, trashItem := range trashItems {
+		var journalEntry models.TrashedJournalEntry
+		if err := json.Unmarshal(trashItem.Data, &journalEntry); err != nil {
+			return err
+		}
+
+		if journalEntry.Title, err = convert(journalEncryptionFieldTitle, journalEntry.Title); err != nil {
+			return err
+		}
+
+		if journalEntry.Body, err = convert(journalEncryptionFieldBody, journalEntry.Body); err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(journalEntry)
+		if err != nil {
+			return err
+		}
+
+		title, err := convert(journalEncryptionFieldTitle, trashItem.Title)
+		if err != nil {
+			return err
+		}
+
+		if err := qtx.UpdateTrashItem(ctx, models.UpdateTrashItemParams{
+			ID:        trashItem.ID,
+			Namespace: namespace,
+			Title:     title,
+			Data:      data,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package persisters
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/tables"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/wikilinks"
+)
+
+var (
+	journalEntryReferenceRegexp = regexp.MustCompile(`(\[\[\s*journal:)([0-9]+)`)
+)
+
+// replaceJournalEntryReferences rewrites the `[[journal:ID]]` links in `body` to the IDs
+// that the journal entries were recreated with, which `journalEntryIDMap` maps old IDs to
+func replaceJournalEntryReferences(body string, journalEntryIDMap map[int32]int32) string {
+	return journalEntryReferenceRegexp.ReplaceAllStringFunc(body, func(reference string) string {
+		matches := journalEntryReferenceRegexp.FindStringSubmatch(reference)
+
+		id, err := strconv.ParseInt(matches[2], 10, 32)
+		if err != nil {
+			return reference
+		}
+
+		newID, ok := journalEntryIDMap[int32(id)]
+		if !ok {
+			return reference
+		}
+
+		return matches[1] + strconv.Itoa(int(newID))
+	})
+}
+
+// GetJournalEntriesMentioningContact returns the journal entries that link to a contact with `[[Contact Name]]`, newest first.
+// The titles of encrypted journal entries are decrypted with `key`, or empty if the journal is locked.
+func (p *Persister) GetJournalEntriesMentioningContact(ctx context.Context, key []byte, id int32, namespace string) ([]models.GetJournalEntriesMentioningContactRow, error) {
+	c, err := getJournalCipher(ctx, p.queries, key, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	journalEntries, err := p.queries.GetJournalEntriesMentioningContact(ctx, models.GetJournalEntriesMentioningContactParams{
+		ID:        id,
+		Namespace: namespace,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, journalEntry := range journalEntries {
+		if journalEntries[i].Title, err = c.openTitle(journalEntry.Title); err != nil {
+			return nil, err
+		}
+	}
+
+	return journalEntries, nil
+}
+
+// setJournalEntryMentions replaces the contacts a journal entry mentions with the ones linked to in its `body`.
+// Names are matched case-insensitively against a contact's full name and nickname.
+func setJournalEntryMentions(
+	ctx context.Context,
+	qtx *tables.Queries,
+
+	body string,
+
+	journalEntryID int32,
+	namespace string,
+) error {
+	if err := qtx.DeleteJournalEntryMentions(ctx, models.DeleteJournalEntryMentionsParams{
+		ID:        journalEntryID,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	names := []string{}
+	for _, name := range wikilinks.ExtractContactNames([]byte(body)) {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || slices.Contains(names, name) {
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	return qtx.CreateJournalEntryMentions(ctx, models.CreateJournalEntryMentionsParams{
+		ID:        journalEntryID,
+		Namespace: namespace,
+		Names:     names,
+	})
+}
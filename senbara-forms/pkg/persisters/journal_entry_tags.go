@@ -0,0 +1,67 @@
+package persisters
+
+import (
+	"context"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/tables"
+)
+
+func (p *Persister) GetJournalEntryTags(ctx context.Context, id int32, namespace string) ([]string, error) {
+	return p.queries.GetJournalEntryTags(ctx, models.GetJournalEntryTagsParams{
+		ID:        id,
+		Namespace: namespace,
+	})
+}
+
+// GetJournalEntryTagsForJournalEntries returns the tags of each of the journal entries with the `ids`
+func (p *Persister) GetJournalEntryTagsForJournalEntries(ctx context.Context, ids []int32, namespace string) (map[int32][]string, error) {
+	rows, err := p.queries.GetJournalEntryTagsForJournalEntries(ctx, models.GetJournalEntryTagsForJournalEntriesParams{
+		Namespace: namespace,
+		Ids:       ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := map[int32][]string{}
+	for _, row := range rows {
+		tags[row.JournalEntryID] = append(tags[row.JournalEntryID], row.Name)
+	}
+
+	return tags, nil
+}
+
+func (p *Persister) GetJournalTags(ctx context.Context, namespace string) ([]models.GetJournalTagsRow, error) {
+	return p.queries.GetJournalTags(ctx, namespace)
+}
+
+// setJournalEntryTags replaces the tags of a journal entry
+func setJournalEntryTags(
+	ctx context.Context,
+	qtx *tables.Queries,
+
+	tags []string,
+
+	journalEntryID int32,
+	namespace string,
+) error {
+	if err := qtx.DeleteJournalEntryTags(ctx, models.DeleteJournalEntryTagsParams{
+		ID:        journalEntryID,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		if err := qtx.CreateJournalEntryTag(ctx, models.CreateJournalEntryTagParams{
+			ID:        journalEntryID,
+			Namespace: namespace,
+			Name:      tag,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
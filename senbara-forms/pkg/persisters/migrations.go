@@ -0,0 +1,86 @@
+package persisters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/migrations"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+)
+
+var (
+	ErrSchemaOutdated = errors.New("database schema is outdated, apply the pending migrations first")
+	ErrSchemaTooNew   = errors.New("database schema is newer than the one this version expects")
+)
+
+func (p *Persister) getMigrationProvider() (*goose.Provider, error) {
+	// Migrations hold a Postgres advisory lock so that concurrent runs, i.e. from multiple replicas
+	// starting at the same time, wait for each other instead of applying the same migration twice
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, err
+	}
+
+	return goose.NewProvider(goose.DialectPostgres, p.db, migrations.FS, goose.WithSessionLocker(locker))
+}
+
+// MigrateUp applies all pending migrations
+func (p *Persister) MigrateUp(ctx context.Context) ([]*goose.MigrationResult, error) {
+	provider, err := p.getMigrationProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.Up(ctx)
+}
+
+// MigrateDown rolls back the most recently applied migration
+func (p *Persister) MigrateDown(ctx context.Context) (*goose.MigrationResult, error) {
+	provider, err := p.getMigrationProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.Down(ctx)
+}
+
+// GetMigrationStatus returns the state of all migrations, sorted by version
+func (p *Persister) GetMigrationStatus(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	provider, err := p.getMigrationProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.Status(ctx)
+}
+
+// CheckMigrations returns an error if the schema of the database doesn't match the migrations
+// of this version. It doesn't wait for the advisory lock, so it can be used while migrations run.
+func (p *Persister) CheckMigrations(ctx context.Context) error {
+	provider, err := p.getMigrationProvider()
+	if err != nil {
+		return err
+	}
+
+	current, target, err := provider.GetVersions(ctx)
+	if err != nil {
+		return err
+	}
+
+	if current > target {
+		return fmt.Errorf("%w: database is at version %v, expected %v", ErrSchemaTooNew, current, target)
+	}
+
+	pending, err := provider.HasPending(ctx)
+	if err != nil {
+		return err
+	}
+
+	if pending {
+		return fmt.Errorf("%w: database is at version %v, expected %v", ErrSchemaOutdated, current, target)
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package persisters
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+)
+
+const (
+	RelationshipKindPartner   = "partner"
+	RelationshipKindParent    = "parent"
+	RelationshipKindChild     = "child"
+	RelationshipKindSibling   = "sibling"
+	RelationshipKindFriend    = "friend"
+	RelationshipKindColleague = "colleague"
+)
+
+var (
+	ErrInvalidRelationshipKind   = errors.New("invalid relationship kind")
+	ErrRelationshipAlreadyExists = errors.New("relationship already exists")
+)
+
+// RelationshipKindInverses maps each relationship kind to the kind seen from the other
+// contact's side, i.e. if Carol is Dave's parent, Dave is Carol's child.
+var RelationshipKindInverses = map[string]string{
+	RelationshipKindPartner:   RelationshipKindPartner,
+	RelationshipKindParent:    RelationshipKindChild,
+	RelationshipKindChild:     RelationshipKindParent,
+	RelationshipKindSibling:   RelationshipKindSibling,
+	RelationshipKindFriend:    RelationshipKindFriend,
+	RelationshipKindColleague: RelationshipKindColleague,
+}
+
+// getRelationshipError maps the errors of creating a relationship, which returns no rows
+// if one of the contacts isn't in the namespace, to the errors that callers can handle
+func getRelationshipError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrContactDoesNotExist
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" {
+		return ErrRelationshipAlreadyExists
+	}
+
+	return err
+}
+
+// CreateRelationship stores that the related contact is the contact's `kind`, and
+// the inverse relationship from the related contact's side in the same transaction.
+func (p *Persister) CreateRelationship(
+	ctx context.Context,
+
+	relatedContactID int32,
+	kind string,
+
+	contactID int32,
+	namespace string,
+) (int32, error) {
+	inverseKind, ok := RelationshipKindInverses[kind]
+	if !ok {
+		return 0, ErrInvalidRelationshipKind
+	}
+
+	tx, err := p.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	qtx := p.queries.WithTx(tx)
+
+	id, err := qtx.CreateRelationship(ctx, models.CreateRelationshipParams{
+		ID:        contactID,
+		ID_2:      relatedContactID,
+		Kind:      kind,
+		Namespace: namespace,
+	})
+	if err != nil {
+		return 0, getRelationshipError(err)
+	}
+
+	if _, err := qtx.CreateRelationship(ctx, models.CreateRelationshipParams{
+		ID:        relatedContactID,
+		ID_2:      contactID,
+		Kind:      inverseKind,
+		Namespace: namespace,
+	}); err != nil {
+		return 0, getRelationshipError(err)
+	}
+
+	return id, tx.Commit()
+}
+
+func (p *Persister) GetRelationships(
+	ctx context.Context,
+
+	contactID int32,
+	namespace string,
+) ([]models.GetRelationshipsRow, error) {
+	return p.queries.GetRelationships(ctx, models.GetRelationshipsParams{
+		ID:        contactID,
+		Namespace: namespace,
+	})
+}
+
+// DeleteRelationship deletes a relationship together with its inverse.
+func (p *Persister) DeleteRelationship(
+	ctx context.Context,
+
+	id int32,
+
+	contactID int32,
+	namespace string,
+) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := p.queries.WithTx(tx)
+
+	relationship, err := qtx.GetRelationship(ctx, models.GetRelationshipParams{
+		ID_2: id,
+
+		ID:        contactID,
+		Namespace: namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteRelationship(ctx, models.DeleteRelationshipParams{
+		ID:               relationship.ContactID,
+		Namespace:        namespace,
+		RelatedContactID: relationship.RelatedContactID,
+		Kind:             relationship.Kind,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteRelationship(ctx, models.DeleteRelationshipParams{
+		ID:               relationship.RelatedContactID,
+		Namespace:        namespace,
+		RelatedContactID: relationship.ContactID,
+		Kind:             RelationshipKindInverses[relationship.Kind],
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,291 @@
+package persisters
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/tables"
+)
+
+const (
+	RevisionEntityNameJournalEntry = "journalEntry"
+	RevisionEntityNameContact      = "contact"
+)
+
+var (
+	ErrUnknownRevisionEntityName = errors.New("unknown revision entity name")
+)
+
+func createRevision(
+	ctx context.Context,
+	qtx *tables.Queries,
+
+	entityName string,
+	entityID int32,
+	data any,
+
+	namespace string,
+) error {
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return qtx.CreateRevision(ctx, models.CreateRevisionParams{
+		EntityName: entityName,
+		EntityID:   entityID,
+		Data:       rawData,
+		Namespace:  namespace,
+	})
+}
+
+func getExportedJournalEntry(ctx context.Context, qtx *tables.Queries, id int32, namespace string) (models.ExportedJournalEntry, error) {
+	journalEntry, err := qtx.GetJournalEntry(ctx, models.GetJournalEntryParams{
+		ID:        id,
+		Namespace: namespace,
+	})
+	if err != nil {
+		return models.ExportedJournalEntry{}, err
+	}
+
+	tags, err := qtx.GetJournalEntryTags(ctx, models.GetJournalEntryTagsParams{
+		ID:        id,
+		Namespace: namespace,
+	})
+	if err != nil {
+		return models.ExportedJournalEntry{}, err
+	}
+
+	return models.ExportedJournalEntry{
+		ID:        journalEntry.ID,
+		Title:     journalEntry.Title,
+		Date:      journalEntry.Date,
+		Body:      journalEntry.Body,
+		Rating:    journalEntry.Rating,
+		Namespace: journalEntry.Namespace,
+		Tags:      tags,
+	}, nil
+}
+
+func getExportedContact(ctx context.Context, qtx *tables.Queries, id int32, namespace string) (models.ExportedContact, error) {
+	contact, err := qtx.GetContact(ctx, models.GetContactParams{
+		ID:        id,
+		Namespace: namespace,
+	})
+	if err != nil {
+		return models.ExportedContact{}, err
+	}
+
+	exportedContact := models.ExportedContact{
+		ID:        contact.ID,
+		FirstName: contact.FirstName,
+		LastName:  contact.LastName,
+		Nickname:  contact.Nickname,
+		Email:     contact.Email,
+		Pronouns:  contact.Pronouns,
+		Namespace: contact.Namespace,
+		Birthday:  contact.Birthday,
+		Address:   contact.Address,
+		Notes:     contact.Notes,
+
+		ContactFrequency: contact.ContactFrequency,
+	}
+
+	customFieldValues, err := qtx.GetCustomFieldValues(ctx, models.GetCustomFieldValuesParams{
+		ID:        id,
+		Namespace: namespace,
+	})
+	if err != nil {
+		return models.ExportedContact{}, err
+	}
+
+	if len(customFieldValues) > 0 {
+		exportedContact.CustomFields = map[string]string{}
+		for _, customFieldValue := range customFieldValues {
+			exportedContact.CustomFields[customFieldValue.Name] = customFieldValue.Value
+		}
+	}
+
+	return exportedContact, nil
+}
+
+// GetJournalEntryRevisions returns the current version of a journal entry and its previous versions, newest first.
+// If journal encryption is enabled, both are decrypted with `key`.
+func (p *Persister) GetJournalEntryRevisions(ctx context.Context, key []byte, id int32, namespace string) (models.ExportedJournalEntry, []models.GetRevisionsRow, error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return models.ExportedJournalEntry{}, nil, err
+	}
+	defer tx.Rollback()
+
+	qtx := p.queries.WithTx(tx)
+
+	c, err := getJournalCipher(ctx, qtx, key, namespace)
+	if err != nil {
+		return models.ExportedJournalEntry{}, nil, err
+	}
+
+	journalEntry, err := getExportedJournalEntry(ctx, qtx, id, namespace)
+	if err != nil {
+		return models.ExportedJournalEntry{}, nil, err
+	}
+
+	if journalEntry.Title, journalEntry.Body, err = c.openJournalEntry(journalEntry.Title, journalEntry.Body); err != nil {
+		return models.ExportedJournalEntry{}, nil, err
+	}
+
+	revisions, err := qtx.GetRevisions(ctx, models.GetRevisionsParams{
+		Namespace:  namespace,
+		EntityName: RevisionEntityNameJournalEntry,
+		EntityID:   id,
+	})
+	if err != nil {
+		return models.ExportedJournalEntry{}, nil, err
+	}
+
+	if c.enabled {
+		for i, revision := range revisions {
+			var revisionJournalEntry models.ExportedJournalEntry
+			if err := json.Unmarshal(revision.Data, &revisionJournalEntry); err != nil {
+				return models.ExportedJournalEntry{}, nil, err
+			}
+
+			if revisionJournalEntry.Title, revisionJournalEntry.Body, err = c.openJournalEntry(revisionJournalEntry.Title, revisionJournalEntry.Body); err != nil {
+				return models.ExportedJournalEntry{}, nil, err
+			}
+
+			if revisions[i].Data, err = json.Marshal(revisionJournalEntry); err != nil {
+				return models.ExportedJournalEntry{}, nil, err
+			}
+		}
+	}
+
+	return journalEntry, revisions, tx.Commit()
+}
+
+// GetContactRevisions returns the current version of a contact and its previous versions, newest first
+func (p *Persister) GetContactRevisions(ctx context.Context, id int32, namespace string) (models.ExportedContact, []models.GetRevisionsRow, error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return models.ExportedContact{}, nil, err
+	}
+	defer tx.Rollback()
+
+	qtx := p.queries.WithTx(tx)
+
+	contact, err := getExportedContact(ctx, qtx, id, namespace)
+	if err != nil {
+		return models.ExportedContact{}, nil, err
+	}
+
+	revisions, err := qtx.GetRevisions(ctx, models.GetRevisionsParams{
+		Namespace:  namespace,
+		EntityName: RevisionEntityNameContact,
+		EntityID:   id,
+	})
+	if err != nil {
+		return models.ExportedContact{}, nil, err
+	}
+
+	return contact, revisions, tx.Commit()
+}
+
+// RestoreRevision replaces an entity with one of its previous versions. The version that is
+// being replaced is stored as a revision itself, so restoring can be undone.
+func (p *Persister) RestoreRevision(ctx context.Context, key []byte, id int32, namespace string) (models.Revision, error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return models.Revision{}, err
+	}
+	defer tx.Rollback()
+
+	qtx := p.queries.WithTx(tx)
+
+	revision, err := qtx.GetRevision(ctx, models.GetRevisionParams{
+		ID:        id,
+		Namespace: namespace,
+	})
+	if err != nil {
+		return models.Revision{}, err
+	}
+
+	switch revision.EntityName {
+	case RevisionEntityNameJournalEntry:
+		var journalEntry models.ExportedJournalEntry
+		if err := json.Unmarshal(revision.Data, &journalEntry); err != nil {
+			return models.Revision{}, err
+		}
+
+		c, err := getJournalCipher(ctx, qtx, key, namespace)
+		if err != nil {
+			return models.Revision{}, err
+		}
+
+		// The journal entry is sealed again when it is updated
+		if journalEntry.Title, journalEntry.Body, err = c.openJournalEntry(journalEntry.Title, journalEntry.Body); err != nil {
+			return models.Revision{}, err
+		}
+
+		if err := updateJournalEntry(
+			ctx,
+			qtx,
+			c,
+
+			revision.EntityID,
+			sql.NullInt32{},
+			journalEntry.Title,
+			journalEntry.Body,
+			journalEntry.Rating,
+			journalEntry.Date,
+			journalEntry.Tags,
+
+			namespace,
+		); err != nil {
+			return models.Revision{}, err
+		}
+
+	case RevisionEntityNameContact:
+		var contact models.ExportedContact
+		if err := json.Unmarshal(revision.Data, &contact); err != nil {
+			return models.Revision{}, err
+		}
+
+		oldContact, err := getExportedContact(ctx, qtx, revision.EntityID, namespace)
+		if err != nil {
+			return models.Revision{}, err
+		}
+
+		if err := createRevision(ctx, qtx, RevisionEntityNameContact, revision.EntityID, oldContact, namespace); err != nil {
+			return models.Revision{}, err
+		}
+
+		if _, err := qtx.UpdateContact(ctx, models.UpdateContactParams{
+			ID:        revision.EntityID,
+			Namespace: namespace,
+			FirstName: contact.FirstName,
+			LastName:  contact.LastName,
+			Nickname:  contact.Nickname,
+			Email:     contact.Email,
+			Pronouns:  contact.Pronouns,
+			Birthday:  contact.Birthday,
+			Address:   contact.Address,
+			Notes:     contact.Notes,
+
+			ContactFrequency: contact.ContactFrequency,
+		}); err != nil {
+			return models.Revision{}, err
+		}
+
+		if err := setCustomFieldValuesByName(ctx, qtx, contact.CustomFields, revision.EntityID, namespace); err != nil {
+			return models.Revision{}, err
+		}
+
+	default:
+		return models.Revision{}, ErrUnknownRevisionEntityName
+	}
+
+	return revision, tx.Commit()
+}
@@ -4,24 +4,35 @@ package persisters
 
 import (
 	"database/sql"
+	"errors"
 
-	"github.com/pojntfx/senbara/senbara-forms/pkg/migrations"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/blobs"
 	"github.com/pojntfx/senbara/senbara-forms/pkg/tables"
-	"github.com/pressly/goose/v3"
+)
+
+var (
+	ErrInvalidSort     = errors.New("invalid sort")
+	ErrVersionConflict = errors.New("entity was changed in the meantime")
 )
 
 type Persister struct {
 	pgaddr  string
+	blobDir string
 	queries *tables.Queries
 	db      *sql.DB
+	blobs   blobs.Store
 }
 
-func NewPersister(pgaddr string) *Persister {
+// NewPersister creates a persister for the database at `pgaddr`. Attachments are stored
+// in `blobDir` if it is set, and as Postgres large objects otherwise.
+func NewPersister(pgaddr, blobDir string) *Persister {
 	return &Persister{
-		pgaddr: pgaddr,
+		pgaddr:  pgaddr,
+		blobDir: blobDir,
 	}
 }
 
+// Init connects to the database. It doesn't apply migrations; see `MigrateUp` for that.
 func (p *Persister) Init() error {
 	var err error
 	p.db, err = sql.Open("postgres", p.pgaddr)
@@ -29,17 +40,18 @@ func (p *Persister) Init() error {
 		return err
 	}
 
-	goose.SetBaseFS(migrations.FS)
+	p.queries = tables.New(p.db)
 
-	if err := goose.SetDialect("postgres"); err != nil {
-		return err
-	}
+	if p.blobDir == "" {
+		p.blobs = blobs.NewPostgresStore(p.queries)
+	} else {
+		store := blobs.NewDirectoryStore(p.blobDir)
+		if err := store.Init(); err != nil {
+			return err
+		}
 
-	if err := goose.Up(p.db, "."); err != nil {
-		return err
+		p.blobs = store
 	}
 
-	p.queries = tables.New(p.db)
-
 	return nil
 }
@@ -0,0 +1,143 @@
+package persisters
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+)
+
+const (
+	SearchEntityNameJournalEntry = "journalEntry"
+	SearchEntityNameContact      = "contact"
+	SearchEntityNameDebt         = "debt"
+	SearchEntityNameActivity     = "activity"
+
+	// These have to match the `StartSel` and `StopSel` options of the `ts_headline` calls in the search query
+	SearchHighlightStart = "\x01"
+	SearchHighlightStop  = "\x02"
+
+	// These approximate the default options and weights of `ts_headline` and `ts_rank`
+	searchHeadlineWordsBefore = 10
+	searchHeadlineWordsAfter  = 25
+	searchTitleWeight         = 1.0
+	searchBodyWeight          = 0.4
+	searchRankScale           = 0.1
+)
+
+// Search returns the journal entries, contacts, debts and activities matching `query`, ranked by relevance.
+// Matches in the headlines are delimited by `SearchHighlightStart` and `SearchHighlightStop`.
+// Encrypted journal entries aren't in the full-text search index; if `key` unlocks them, they are
+// decrypted and searched for the words of `query` instead.
+func (p *Persister) Search(ctx context.Context, key []byte, query string, limit int32, namespace string) ([]models.SearchRow, error) {
+	c, err := getJournalCipher(ctx, p.queries, key, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := p.queries.Search(ctx, models.SearchParams{
+		Namespace: namespace,
+		Query:     query,
+		RowLimit:  limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.enabled || c.locked() {
+		return rows, nil
+	}
+
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return rows, nil
+	}
+
+	journalEntries, err := p.queries.GetJournalEntries(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, journalEntry := range journalEntries {
+		title, body, err := c.openJournalEntry(journalEntry.Title, journalEntry.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		rank, ok := getSearchRank(title, body, terms)
+		if !ok {
+			continue
+		}
+
+		rows = append(rows, models.SearchRow{
+			EntityName: SearchEntityNameJournalEntry,
+			ID:         journalEntry.ID,
+			Title:      title,
+			Headline:   getSearchHeadline(body, terms),
+			Rank:       rank,
+		})
+	}
+
+	slices.SortStableFunc(rows, func(a, b models.SearchRow) int {
+		return cmp.Compare(b.Rank, a.Rank)
+	})
+
+	if len(rows) > int(limit) {
+		rows = rows[:limit]
+	}
+
+	return rows, nil
+}
+
+// getSearchRank ranks a decrypted journal entry by how often the `terms` occur in it. Like
+// with the full-text search, all of the terms have to occur for the journal entry to match.
+func getSearchRank(title, body string, terms []string) (float32, bool) {
+	title = strings.ToLower(title)
+	body = strings.ToLower(body)
+
+	var rank float32
+	for _, term := range terms {
+		titleMatches := strings.Count(title, term)
+		bodyMatches := strings.Count(body, term)
+
+		if titleMatches == 0 && bodyMatches == 0 {
+			return 0, false
+		}
+
+		rank += searchRankScale * (searchTitleWeight*float32(titleMatches) + searchBodyWeight*float32(bodyMatches))
+	}
+
+	return rank, true
+}
+
+// getSearchHeadline returns the words of `body` around the first match of any of the `terms`, highlighting all matches
+func getSearchHeadline(body string, terms []string) string {
+	words := strings.Fields(body)
+
+	matches := func(word string) bool {
+		return slices.ContainsFunc(terms, func(term string) bool {
+			return strings.Contains(strings.ToLower(word), term)
+		})
+	}
+
+	start := slices.IndexFunc(words, matches)
+	if start < 0 {
+		start = 0
+	}
+
+	start = max(0, start-searchHeadlineWordsBefore)
+	end := min(len(words), start+searchHeadlineWordsBefore+searchHeadlineWordsAfter)
+
+	headline := make([]string, 0, end-start)
+	for _, word := range words[start:end] {
+		if matches(word) {
+			word = SearchHighlightStart + word + SearchHighlightStop
+		}
+
+		headline = append(headline, word)
+	}
+
+	return strings.Join(headline, " ")
+}
@@ -0,0 +1,124 @@
+package persisters
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+)
+
+const (
+	DefaultTimeZone = "UTC"
+
+	SearchLanguageSimple  = "simple"
+	SearchLanguageEnglish = "english"
+	SearchLanguageGerman  = "german"
+	SearchLanguageFrench  = "french"
+
+	DefaultSearchLanguage = SearchLanguageSimple
+
+	PreviewFormatText = "text"
+	PreviewFormatHTML = "html"
+
+	DefaultPreviewFormat = PreviewFormatText
+)
+
+var (
+	ErrInvalidSearchLanguage = errors.New("invalid search language")
+	ErrInvalidPreviewFormat  = errors.New("invalid preview format")
+)
+
+// SearchLanguages are the PostgreSQL text search configurations that can be used for stemming
+var SearchLanguages = []string{
+	SearchLanguageSimple,
+	SearchLanguageEnglish,
+	SearchLanguageGerman,
+	SearchLanguageFrench,
+}
+
+// PreviewFormats are the formats that previews of Markdown, e.g. in the journal list, can be shown in
+var PreviewFormats = []string{
+	PreviewFormatText,
+	PreviewFormatHTML,
+}
+
+// GetSettings returns the settings of a namespace, or the defaults if they were never changed.
+func (p *Persister) GetSettings(ctx context.Context, namespace string) (models.Setting, error) {
+	settings, err := p.queries.GetSettings(ctx, namespace)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Setting{
+				Namespace: namespace,
+				TimeZone:  DefaultTimeZone,
+
+				SearchLanguage: DefaultSearchLanguage,
+				PreviewFormat:  DefaultPreviewFormat,
+			}, nil
+		}
+
+		return models.Setting{}, err
+	}
+
+	return settings, nil
+}
+
+// UpdateSettings stores the settings of a namespace. If the search language changed, the
+// search vectors of the namespace are rebuilt so that they use the new stemming rules.
+func (p *Persister) UpdateSettings(ctx context.Context, timeZone, searchLanguage, previewFormat, namespace string) error {
+	if !slices.Contains(SearchLanguages, searchLanguage) {
+		return ErrInvalidSearchLanguage
+	}
+
+	if !slices.Contains(PreviewFormats, previewFormat) {
+		return ErrInvalidPreviewFormat
+	}
+
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := p.queries.WithTx(tx)
+
+	oldSearchLanguage := DefaultSearchLanguage
+	settings, err := qtx.GetSettings(ctx, namespace)
+	if err == nil {
+		oldSearchLanguage = settings.SearchLanguage
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if err := qtx.UpdateSettings(ctx, models.UpdateSettingsParams{
+		Namespace: namespace,
+		TimeZone:  timeZone,
+
+		SearchLanguage: searchLanguage,
+		PreviewFormat:  previewFormat,
+	}); err != nil {
+		return err
+	}
+
+	if searchLanguage != oldSearchLanguage {
+		// The triggers re-compute the search vectors with the new language when they are reset
+		if err := qtx.ReindexJournalEntriesForNamespace(ctx, namespace); err != nil {
+			return err
+		}
+
+		if err := qtx.ReindexContactsForNamespace(ctx, namespace); err != nil {
+			return err
+		}
+
+		if err := qtx.ReindexDebtsForNamespace(ctx, namespace); err != nil {
+			return err
+		}
+
+		if err := qtx.ReindexActivitiesForNamespace(ctx, namespace); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,491 @@
+package persisters
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/tables"
+)
+
+const (
+	TrashEntityNameJournalEntry = "journalEntry"
+	TrashEntityNameContact      = "contact"
+	TrashEntityNameDebt         = "debt"
+	TrashEntityNameActivity     = "activity"
+)
+
+var (
+	ErrUnknownTrashEntityName = errors.New("unknown trash entity name")
+)
+
+// createTrashItem stores a snapshot of an entity before it is deleted. Since it is restored
+// with its original ID, everything else that references the entity by ID stays valid.
+func createTrashItem(
+	ctx context.Context,
+	qtx *tables.Queries,
+
+	entityName string,
+	entityID int32,
+	title string,
+	data any,
+
+	namespace string,
+) error {
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = qtx.CreateTrashItem(ctx, models.CreateTrashItemParams{
+		EntityName: entityName,
+		EntityID:   entityID,
+		Title:      title,
+		Data:       rawData,
+		Namespace:  namespace,
+	})
+
+	return err
+}
+
+func getDebtTitle(amount float64, currency, description string) string {
+	if description == "" {
+		return fmt.Sprintf("%v %v", math.Abs(amount), currency)
+	}
+
+	return fmt.Sprintf("%v %v: %v", math.Abs(amount), currency, description)
+}
+
+// GetTrashItems returns the trash items of a namespace that haven't expired yet. The titles of
+// encrypted journal entries are decrypted with `key`, or empty if the journal is locked.
+func (p *Persister) GetTrashItems(ctx context.Context, key []byte, namespace string) ([]models.GetTrashItemsRow, error) {
+	c, err := getJournalCipher(ctx, p.queries, key, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	trashItems, err := p.queries.GetTrashItems(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, trashItem := range trashItems {
+		if trashItem.EntityName != TrashEntityNameJournalEntry {
+			continue
+		}
+
+		if trashItems[i].Title, err = c.openTitle(trashItem.Title); err != nil {
+			return nil, err
+		}
+	}
+
+	return trashItems, nil
+}
+
+// DeleteTrashItem permanently deletes a trash item, including the revisions of its entity
+func (p *Persister) DeleteTrashItem(ctx context.Context, id int32, namespace string) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := p.queries.WithTx(tx)
+
+	if err := qtx.DeleteRevisionsForTrashItem(ctx, models.DeleteRevisionsForTrashItemParams{
+		ID:        id,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteTrashItem(ctx, models.DeleteTrashItemParams{
+		ID:        id,
+		Namespace: namespace,
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// PurgeTrash permanently deletes all trash items of all namespaces that have expired,
+// including the revisions of their entities, and returns how many were deleted.
+func (p *Persister) PurgeTrash(ctx context.Context) (int64, error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	qtx := p.queries.WithTx(tx)
+
+	if err := qtx.DeleteRevisionsForExpiredTrashItems(ctx); err != nil {
+		return 0, err
+	}
+
+	purged, err := qtx.PurgeTrashItems(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return purged, tx.Commit()
+}
+
+func (p *Persister) RestoreTrashItem(ctx context.Context, key []byte, id int32, namespace string) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := p.queries.WithTx(tx)
+
+	trashItem, err := qtx.GetTrashItem(ctx, models.GetTrashItemParams{
+		ID:        id,
+		Namespace: namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Blobs are created outside of the transaction, so they have to be removed again if it doesn't commit
+	blobKeys := []string{}
+	defer func() {
+		_ = p.deleteBlobs(ctx, blobKeys)
+	}()
+
+	if err := p.restoreTrashItem(ctx, qtx, key, trashItem, &blobKeys); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	blobKeys = nil
+
+	return nil
+}
+
+// restoreTrashItem recreates the entity of a trash item with its original ID and removes the trash item.
+// Trash items that the entity depends on, like the contact of a debt, are restored along with it.
+func (p *Persister) restoreTrashItem(
+	ctx context.Context,
+	qtx *tables.Queries,
+
+	key []byte,
+	trashItem models.TrashItem,
+	blobKeys *[]string,
+) error {
+	namespace := trashItem.Namespace
+
+	switch trashItem.EntityName {
+	case TrashEntityNameJournalEntry:
+		var journalEntry models.TrashedJournalEntry
+		if err := json.Unmarshal(trashItem.Data, &journalEntry); err != nil {
+			return err
+		}
+
+		c, err := getJournalCipher(ctx, qtx, key, namespace)
+		if err != nil {
+			return err
+		}
+
+		// The stored title and body are still sealed, but the body is needed
+		// in plaintext to find the mentions in it
+		body, err := c.open(journalEncryptionFieldBody, journalEntry.Body)
+		if err != nil {
+			return err
+		}
+
+		if err := qtx.RestoreJournalEntry(ctx, models.RestoreJournalEntryParams{
+			ID:        trashItem.EntityID,
+			Title:     journalEntry.Title,
+			Date:      journalEntry.Date,
+			Body:      journalEntry.Body,
+			Rating:    journalEntry.Rating,
+			Namespace: namespace,
+		}); err != nil {
+			return err
+		}
+
+		if err := setJournalEntryTags(ctx, qtx, journalEntry.Tags, trashItem.EntityID, namespace); err != nil {
+			return err
+		}
+
+		if err := setJournalEntryMentions(ctx, qtx, body, trashItem.EntityID, namespace); err != nil {
+			return err
+		}
+
+		// Attachments keep their IDs too, so the references to them in the body don't have to be rewritten
+		for _, attachment := range journalEntry.Attachments {
+			blobKey, err := p.restoreAttachment(ctx, qtx, attachment, trashItem.EntityID, namespace)
+			if err != nil {
+				return err
+			}
+
+			*blobKeys = append(*blobKeys, blobKey)
+		}
+
+	case TrashEntityNameContact:
+		var contact models.TrashedContact
+		if err := json.Unmarshal(trashItem.Data, &contact); err != nil {
+			return err
+		}
+
+		contactID := trashItem.EntityID
+
+		if err := qtx.RestoreContact(ctx, models.RestoreContactParams{
+			ID:               contactID,
+			FirstName:        contact.Contact.FirstName,
+			LastName:         contact.Contact.LastName,
+			Nickname:         contact.Contact.Nickname,
+			Email:            contact.Contact.Email,
+			Pronouns:         contact.Contact.Pronouns,
+			Namespace:        namespace,
+			Birthday:         contact.Contact.Birthday,
+			Address:          contact.Contact.Address,
+			Notes:            contact.Contact.Notes,
+			ContactFrequency: contact.Contact.ContactFrequency,
+		}); err != nil {
+			return err
+		}
+
+		if err := setCustomFieldValuesByName(ctx, qtx, contact.Contact.CustomFields, contactID, namespace); err != nil {
+			return err
+		}
+
+		for _, debt := range contact.Debts {
+			if _, err := qtx.RestoreDebt(ctx, models.RestoreDebtParams{
+				ID:          debt.ID,
+				Amount:      debt.Amount,
+				Currency:    debt.Currency,
+				Description: debt.Description,
+				ContactID:   contactID,
+				Namespace:   namespace,
+			}); err != nil {
+				return err
+			}
+		}
+
+		for _, activity := range contact.Activities {
+			if _, err := qtx.RestoreActivity(ctx, models.RestoreActivityParams{
+				ID:          activity.ID,
+				Name:        activity.Name,
+				Date:        activity.Date,
+				Description: activity.Description,
+				ContactID:   contactID,
+				Namespace:   namespace,
+			}); err != nil {
+				return err
+			}
+		}
+
+		for _, relationship := range contact.Relationships {
+			if err := restoreTrashedRelationship(ctx, qtx, contactID, relationship, namespace); err != nil {
+				return err
+			}
+		}
+
+		for _, contactPhoto := range contact.ContactPhotos {
+			if _, err := qtx.CreateContactPhoto(ctx, models.CreateContactPhotoParams{
+				ID:          contactID,
+				Namespace:   namespace,
+				Size:        contactPhoto.Size,
+				ContentType: contactPhoto.ContentType,
+				Data:        contactPhoto.Data,
+			}); err != nil {
+				return err
+			}
+		}
+
+		// Journal entries that have been deleted since get their mentions back from their body once they are restored
+		if len(contact.MentioningJournalEntryIDs) > 0 {
+			if err := qtx.CreateJournalEntryMentionsForContact(ctx, models.CreateJournalEntryMentionsForContactParams{
+				ID:              contactID,
+				Namespace:       namespace,
+				JournalEntryIds: contact.MentioningJournalEntryIDs,
+			}); err != nil {
+				return err
+			}
+		}
+
+	case TrashEntityNameDebt:
+		var debt models.ExportedDebt
+		if err := json.Unmarshal(trashItem.Data, &debt); err != nil {
+			return err
+		}
+
+		restoreDebt := func() (int64, error) {
+			return qtx.RestoreDebt(ctx, models.RestoreDebtParams{
+				ID:          trashItem.EntityID,
+				Amount:      debt.Amount,
+				Currency:    debt.Currency,
+				Description: debt.Description,
+				ContactID:   debt.ContactID.Int32,
+				Namespace:   namespace,
+			})
+		}
+
+		if err := p.restoreWithContact(ctx, qtx, key, debt.ContactID.Int32, namespace, blobKeys, restoreDebt); err != nil {
+			return err
+		}
+
+	case TrashEntityNameActivity:
+		var activity models.ExportedActivity
+		if err := json.Unmarshal(trashItem.Data, &activity); err != nil {
+			return err
+		}
+
+		restoreActivity := func() (int64, error) {
+			return qtx.RestoreActivity(ctx, models.RestoreActivityParams{
+				ID:          trashItem.EntityID,
+				Name:        activity.Name,
+				Date:        activity.Date,
+				Description: activity.Description,
+				ContactID:   activity.ContactID.Int32,
+				Namespace:   namespace,
+			})
+		}
+
+		if err := p.restoreWithContact(ctx, qtx, key, activity.ContactID.Int32, namespace, blobKeys, restoreActivity); err != nil {
+			return err
+		}
+
+	default:
+		return ErrUnknownTrashEntityName
+	}
+
+	return qtx.DeleteTrashItem(ctx, models.DeleteTrashItemParams{
+		ID:        trashItem.ID,
+		Namespace: namespace,
+	})
+}
+
+// restoreWithContact calls `restore`, which has to return the number of restored rows. If nothing
+// was restored because the contact is in the trash too, the contact is restored first.
+func (p *Persister) restoreWithContact(
+	ctx context.Context,
+	qtx *tables.Queries,
+
+	key []byte,
+	contactID int32,
+	namespace string,
+	blobKeys *[]string,
+
+	restore func() (int64, error),
+) error {
+	restored, err := restore()
+	if err != nil {
+		return err
+	}
+
+	if restored > 0 {
+		return nil
+	}
+
+	trashItem, err := qtx.GetTrashItemForEntity(ctx, models.GetTrashItemForEntityParams{
+		Namespace:  namespace,
+		EntityName: TrashEntityNameContact,
+		EntityID:   contactID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrContactDoesNotExist
+		}
+
+		return err
+	}
+
+	if err := p.restoreTrashItem(ctx, qtx, key, trashItem, blobKeys); err != nil {
+		return err
+	}
+
+	if restored, err = restore(); err != nil {
+		return err
+	} else if restored == 0 {
+		return ErrContactDoesNotExist
+	}
+
+	return nil
+}
+
+// restoreTrashedRelationship recreates both directions of a relationship of a restored contact. If the
+// related contact is in the trash too, the relationship is added to its trash item instead, so that it
+// is recreated once that contact is restored.
+func restoreTrashedRelationship(
+	ctx context.Context,
+	qtx *tables.Queries,
+
+	contactID int32,
+	relationship models.TrashedRelationship,
+	namespace string,
+) error {
+	if _, err := qtx.CreateRelationship(ctx, models.CreateRelationshipParams{
+		ID:        contactID,
+		ID_2:      relationship.RelatedContactID,
+		Kind:      relationship.Kind,
+		Namespace: namespace,
+	}); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		trashItem, err := qtx.GetTrashItemForEntity(ctx, models.GetTrashItemForEntityParams{
+			Namespace:  namespace,
+			EntityName: TrashEntityNameContact,
+			EntityID:   relationship.RelatedContactID,
+		})
+		if err != nil {
+			// The related contact has been purged, so the relationship is dropped
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+
+			return err
+		}
+
+		var relatedContact models.TrashedContact
+		if err := json.Unmarshal(trashItem.Data, &relatedContact); err != nil {
+			return err
+		}
+
+		inverseRelationship := models.TrashedRelationship{
+			RelatedContactID: contactID,
+			Kind:             RelationshipKindInverses[relationship.Kind],
+		}
+
+		if slices.Contains(relatedContact.Relationships, inverseRelationship) {
+			return nil
+		}
+
+		relatedContact.Relationships = append(relatedContact.Relationships, inverseRelationship)
+
+		data, err := json.Marshal(relatedContact)
+		if err != nil {
+			return err
+		}
+
+		return qtx.UpdateTrashItem(ctx, models.UpdateTrashItemParams{
+			ID:        trashItem.ID,
+			Namespace: namespace,
+			Title:     trashItem.Title,
+			Data:      data,
+		})
+	}
+
+	_, err := qtx.CreateRelationship(ctx, models.CreateRelationshipParams{
+		ID:        relationship.RelatedContactID,
+		ID_2:      contactID,
+		Kind:      RelationshipKindInverses[relationship.Kind],
+		Namespace: namespace,
+	})
+
+	return err
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"io"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
 )
@@ -13,15 +16,24 @@ var (
 	ErrContactDoesNotExist = errors.New("contact does not exist")
 )
 
+// GetUserData exports all data of a namespace. Encrypted journal entries are decrypted with `key`. If `key` can't
+// decrypt them and `onJournalSkipped` is set, the journal entries and their attachments are skipped instead of
+// failing, and `onJournalSkipped` is called.
 func (p *Persister) GetUserData(
 	ctx context.Context,
 
+	key []byte,
 	namespace string,
 
 	onJournalEntry func(journalEntry models.ExportedJournalEntry) error,
 	onContact func(contact models.ExportedContact) error,
 	onDebt func(debt models.ExportedDebt) error,
 	onActivity func(activity models.ExportedActivity) error,
+	onContactPhoto func(contactPhoto models.ExportedContactPhoto) error,
+	onCustomField func(customField models.ExportedCustomField) error,
+	onAttachment func(attachment models.ExportedAttachment, data io.Reader) error,
+	onRelationship func(relationship models.ExportedRelationship) error,
+	onJournalSkipped func() error,
 ) error {
 	tx, err := p.db.Begin()
 	if err != nil {
@@ -31,24 +43,82 @@ func (p *Persister) GetUserData(
 
 	qtx := p.queries.WithTx(tx)
 
-	journalEntries, err := qtx.GetJournalEntriesExportForNamespace(ctx, namespace)
+	c, err := getJournalCipher(ctx, qtx, key, namespace)
 	if err != nil {
 		return err
 	}
 
-	for _, journalEntry := range journalEntries {
-		if err := onJournalEntry(models.ExportedJournalEntry{
-			ID:        journalEntry.ID,
-			Title:     journalEntry.Title,
-			Date:      journalEntry.Date,
-			Body:      journalEntry.Body,
-			Rating:    journalEntry.Rating,
-			Namespace: journalEntry.Namespace,
+	skipJournal := onJournalSkipped != nil && c.locked()
+	if skipJournal {
+		if err := onJournalSkipped(); err != nil {
+			return err
+		}
+	} else {
+		journalEntries, err := qtx.GetJournalEntriesExportForNamespace(ctx, namespace)
+		if err != nil {
+			return err
+		}
+
+		journalEntryTags, err := qtx.GetJournalEntryTagsExportForNamespace(ctx, namespace)
+		if err != nil {
+			return err
+		}
+
+		tags := map[int32][]string{}
+		for _, journalEntryTag := range journalEntryTags {
+			tags[journalEntryTag.JournalEntryID] = append(tags[journalEntryTag.JournalEntryID], journalEntryTag.Name)
+		}
+
+		for _, journalEntry := range journalEntries {
+			title, body, err := c.openJournalEntry(journalEntry.Title, journalEntry.Body)
+			if err != nil {
+				return err
+			}
+
+			if err := onJournalEntry(models.ExportedJournalEntry{
+				ID:        journalEntry.ID,
+				Title:     title,
+				Date:      journalEntry.Date,
+				Body:      body,
+				Rating:    journalEntry.Rating,
+				Namespace: journalEntry.Namespace,
+				Tags:      tags[journalEntry.ID],
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	customFields, err := qtx.GetCustomFieldsExportForNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	for _, customField := range customFields {
+		if err := onCustomField(models.ExportedCustomField{
+			ID:      customField.ID,
+			Name:    customField.Name,
+			Kind:    customField.Kind,
+			Options: customField.Options,
 		}); err != nil {
 			return err
 		}
 	}
 
+	customFieldValues, err := qtx.GetCustomFieldValuesExportForNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	customFieldValuesByContact := map[int32]map[string]string{}
+	for _, customFieldValue := range customFieldValues {
+		if _, ok := customFieldValuesByContact[customFieldValue.ContactID]; !ok {
+			customFieldValuesByContact[customFieldValue.ContactID] = map[string]string{}
+		}
+
+		customFieldValuesByContact[customFieldValue.ContactID][customFieldValue.Name] = customFieldValue.Value
+	}
+
 	contacts, err := qtx.GetContactsExportForNamespace(ctx, namespace)
 	if err != nil {
 		return err
@@ -66,6 +136,10 @@ func (p *Persister) GetUserData(
 			Birthday:  contact.Birthday,
 			Address:   contact.Address,
 			Notes:     contact.Notes,
+
+			ContactFrequency: contact.ContactFrequency,
+
+			CustomFields: customFieldValuesByContact[contact.ID],
 		}); err != nil {
 			return err
 		}
@@ -105,9 +179,94 @@ func (p *Persister) GetUserData(
 		}
 	}
 
+	contactPhotos, err := qtx.GetContactPhotosExportForNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	for _, contactPhoto := range contactPhotos {
+		if err := onContactPhoto(models.ExportedContactPhoto{
+			ID:          contactPhoto.ID,
+			Size:        contactPhoto.Size,
+			ContentType: contactPhoto.ContentType,
+			Data:        contactPhoto.Data,
+			ContactID: sql.NullInt32{
+				Int32: contactPhoto.ContactID,
+				Valid: true,
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Both directions of a relationship are exported, so that they can be imported as they are
+	relationships, err := qtx.GetRelationshipsExportForNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	for _, relationship := range relationships {
+		if err := onRelationship(models.ExportedRelationship{
+			ID:   relationship.ID,
+			Kind: relationship.Kind,
+			ContactID: sql.NullInt32{
+				Int32: relationship.ContactID,
+				Valid: true,
+			},
+			RelatedContactID: sql.NullInt32{
+				Int32: relationship.RelatedContactID,
+				Valid: true,
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Attachments can't be imported without their journal entries
+	if skipJournal {
+		return nil
+	}
+
+	attachments, err := qtx.GetAttachmentsExportForNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	for _, attachment := range attachments {
+		if err := p.exportAttachment(ctx, attachment, onAttachment); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// exportAttachment passes an attachment to `onAttachment` together with a reader for its contents,
+// so that large attachments don't have to be read into memory
+func (p *Persister) exportAttachment(
+	ctx context.Context,
+
+	attachment models.GetAttachmentsExportForNamespaceRow,
+
+	onAttachment func(attachment models.ExportedAttachment, data io.Reader) error,
+) error {
+	data, err := p.blobs.Open(ctx, attachment.BlobKey)
+	if err != nil {
+		return err
+	}
+	defer data.Close()
+
+	return onAttachment(models.ExportedAttachment{
+		ID:          attachment.ID,
+		Name:        attachment.Name,
+		ContentType: attachment.ContentType,
+		JournalEntryID: sql.NullInt32{
+			Int32: attachment.JournalEntryID,
+			Valid: true,
+		},
+	}, data)
+}
+
 func (p *Persister) DeleteUserData(ctx context.Context, namespace string) error {
 	tx, err := p.db.Begin()
 	if err != nil {
@@ -121,26 +280,80 @@ func (p *Persister) DeleteUserData(ctx context.Context, namespace string) error
 		return err
 	}
 
+	if err := qtx.DeleteContactPhotosForNamespace(ctx, namespace); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteRelationshipsForNamespace(ctx, namespace); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteTrashItemsForNamespace(ctx, namespace); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteRevisionsForNamespace(ctx, namespace); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteCustomFieldValuesForNamespace(ctx, namespace); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteCustomFieldsForNamespace(ctx, namespace); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteSettingsForNamespace(ctx, namespace); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteBackupRunsForNamespace(ctx, namespace); err != nil {
+		return err
+	}
+
 	if err := qtx.DeleteDebtsForNamespace(ctx, namespace); err != nil {
 		return err
 	}
 
+	if err := qtx.DeleteJournalEntryMentionsForNamespace(ctx, namespace); err != nil {
+		return err
+	}
+
 	if err := qtx.DeleteContactsForNamespace(ctx, namespace); err != nil {
 		return err
 	}
 
+	if err := qtx.DeleteJournalEntryTagsForNamespace(ctx, namespace); err != nil {
+		return err
+	}
+
+	blobKeys, err := qtx.DeleteAttachmentsForNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
 	if err := qtx.DeleteJournalEntriesForNamespace(ctx, namespace); err != nil {
 		return err
 	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return p.deleteBlobs(ctx, blobKeys)
 }
 
-func (p *Persister) CreateUserData(ctx context.Context, namespace string) (
+// CreateUserData imports data into a namespace. Journal entries are encrypted with `key` if journal encryption is enabled.
+func (p *Persister) CreateUserData(ctx context.Context, key []byte, namespace string) (
 	createJournalEntry func(journalEntry models.ExportedJournalEntry) error,
 	createContact func(contact models.ExportedContact) error,
 	createDebt func(debt models.ExportedDebt) error,
 	createActivity func(activty models.ExportedActivity) error,
+	createContactPhoto func(contactPhoto models.ExportedContactPhoto) error,
+	createCustomField func(customField models.ExportedCustomField) error,
+	createAttachment func(attachment models.ExportedAttachment) error,
+	createRelationship func(relationship models.ExportedRelationship) error,
 
 	commit func() error,
 	rollback func() error,
@@ -151,10 +364,19 @@ func (p *Persister) CreateUserData(ctx context.Context, namespace string) (
 	createContact = func(contact models.ExportedContact) error { return nil }
 	createDebt = func(debt models.ExportedDebt) error { return nil }
 	createActivity = func(activity models.ExportedActivity) error { return nil }
+	createContactPhoto = func(contactPhoto models.ExportedContactPhoto) error { return nil }
+	createCustomField = func(customField models.ExportedCustomField) error { return nil }
+	createAttachment = func(attachment models.ExportedAttachment) error { return nil }
+	createRelationship = func(relationship models.ExportedRelationship) error { return nil }
 
 	commit = func() error { return nil }
 	rollback = func() error { return nil }
 
+	c, err := getJournalCipher(ctx, p.queries, key, namespace)
+	if err != nil {
+		return
+	}
+
 	var tx *sql.Tx
 	tx, err = p.db.Begin()
 	if err != nil {
@@ -169,9 +391,21 @@ func (p *Persister) CreateUserData(ctx context.Context, namespace string) (
 	)
 
 	createJournalEntry = func(journalEntry models.ExportedJournalEntry) error {
+		// Fall back to the current time if the export doesn't include a date
+		date := journalEntry.Date
+		if date.IsZero() {
+			date = time.Now()
+		}
+
+		title, body, err := c.sealJournalEntry(journalEntry.Title, journalEntry.Body)
+		if err != nil {
+			return err
+		}
+
 		id, err := qtx.CreateJournalEntry(ctx, models.CreateJournalEntryParams{
-			Title:  journalEntry.Title,
-			Body:   journalEntry.Body,
+			Title:  title,
+			Date:   date.UTC(),
+			Body:   body,
 			Rating: journalEntry.Rating,
 
 			Namespace: namespace,
@@ -180,6 +414,10 @@ func (p *Persister) CreateUserData(ctx context.Context, namespace string) (
 			return err
 		}
 
+		if err := setJournalEntryTags(ctx, qtx, journalEntry.Tags, id, namespace); err != nil {
+			return err
+		}
+
 		journalEntryIDMapLock.Lock()
 		defer journalEntryIDMapLock.Unlock()
 
@@ -188,34 +426,65 @@ func (p *Persister) CreateUserData(ctx context.Context, namespace string) (
 		return nil
 	}
 
-	// TODO: Import debts and activities, use `journalEntryIDMap` to resolve external
-	// to internal/actual IDs
+	var (
+		contactIDMapLock sync.Mutex
+		contactIDMap     = map[int32]int32{}
+	)
 
 	createContact = func(contact models.ExportedContact) error {
-		if _, err := qtx.CreateContact(ctx, models.CreateContactParams{
+		id, err := qtx.CreateContact(ctx, models.CreateContactParams{
 			FirstName: contact.FirstName,
 			LastName:  contact.LastName,
 			Nickname:  contact.Nickname,
 			Email:     contact.Email,
 			Pronouns:  contact.Pronouns,
 
+			Namespace: namespace,
+		})
+		if err != nil {
+			return err
+		}
+
+		// CreateContact only sets the required fields, so the rest is set in a second step
+		if _, err := qtx.UpdateContact(ctx, models.UpdateContactParams{
+			ID:        id,
+			FirstName: contact.FirstName,
+			LastName:  contact.LastName,
+			Nickname:  contact.Nickname,
+			Email:     contact.Email,
+			Pronouns:  contact.Pronouns,
+			Birthday:  contact.Birthday,
+			Address:   contact.Address,
+			Notes:     contact.Notes,
+
+			ContactFrequency: contact.ContactFrequency,
+
 			Namespace: namespace,
 		}); err != nil {
 			return err
 		}
 
+		if err := setCustomFieldValuesByName(ctx, qtx, contact.CustomFields, id, namespace); err != nil {
+			return err
+		}
+
+		contactIDMapLock.Lock()
+		defer contactIDMapLock.Unlock()
+
+		contactIDMap[contact.ID] = id
+
 		return nil
 	}
 
 	createDebt = func(debt models.ExportedDebt) error {
-		journalEntryIDMapLock.Lock()
-		defer journalEntryIDMapLock.Unlock()
+		contactIDMapLock.Lock()
+		defer contactIDMapLock.Unlock()
 
 		if !debt.ContactID.Valid {
 			return ErrContactDoesNotExist
 		}
 
-		actualContactID, ok := journalEntryIDMap[debt.ContactID.Int32]
+		actualContactID, ok := contactIDMap[debt.ContactID.Int32]
 		if !ok {
 			return ErrContactDoesNotExist
 		}
@@ -235,14 +504,14 @@ func (p *Persister) CreateUserData(ctx context.Context, namespace string) (
 	}
 
 	createActivity = func(activity models.ExportedActivity) error {
-		journalEntryIDMapLock.Lock()
-		defer journalEntryIDMapLock.Unlock()
+		contactIDMapLock.Lock()
+		defer contactIDMapLock.Unlock()
 
 		if !activity.ContactID.Valid {
 			return ErrContactDoesNotExist
 		}
 
-		actualContactID, ok := journalEntryIDMap[activity.ContactID.Int32]
+		actualContactID, ok := contactIDMap[activity.ContactID.Int32]
 		if !ok {
 			return ErrContactDoesNotExist
 		}
@@ -261,8 +530,190 @@ func (p *Persister) CreateUserData(ctx context.Context, namespace string) (
 		return nil
 	}
 
-	commit = tx.Commit
-	rollback = tx.Rollback
+	createContactPhoto = func(contactPhoto models.ExportedContactPhoto) error {
+		contactIDMapLock.Lock()
+		defer contactIDMapLock.Unlock()
+
+		if !contactPhoto.ContactID.Valid {
+			return ErrContactDoesNotExist
+		}
+
+		actualContactID, ok := contactIDMap[contactPhoto.ContactID.Int32]
+		if !ok {
+			return ErrContactDoesNotExist
+		}
+
+		if _, err := qtx.CreateContactPhoto(ctx, models.CreateContactPhotoParams{
+			ID:          actualContactID,
+			Size:        contactPhoto.Size,
+			ContentType: contactPhoto.ContentType,
+			Data:        contactPhoto.Data,
+
+			Namespace: namespace,
+		}); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	createRelationship = func(relationship models.ExportedRelationship) error {
+		contactIDMapLock.Lock()
+		defer contactIDMapLock.Unlock()
+
+		if _, ok := RelationshipKindInverses[relationship.Kind]; !ok {
+			return ErrInvalidRelationshipKind
+		}
+
+		if !relationship.ContactID.Valid || !relationship.RelatedContactID.Valid {
+			return ErrContactDoesNotExist
+		}
+
+		actualContactID, ok := contactIDMap[relationship.ContactID.Int32]
+		if !ok {
+			return ErrContactDoesNotExist
+		}
+
+		actualRelatedContactID, ok := contactIDMap[relationship.RelatedContactID.Int32]
+		if !ok {
+			return ErrContactDoesNotExist
+		}
+
+		if _, err := qtx.CreateRelationship(ctx, models.CreateRelationshipParams{
+			ID:        actualContactID,
+			ID_2:      actualRelatedContactID,
+			Kind:      relationship.Kind,
+			Namespace: namespace,
+		}); err != nil {
+			return getRelationshipError(err)
+		}
+
+		return nil
+	}
+
+	createCustomField = func(customField models.ExportedCustomField) error {
+		if !slices.Contains(CustomFieldKinds, customField.Kind) {
+			return ErrInvalidCustomFieldKind
+		}
+
+		options := customField.Options
+		if options == nil {
+			options = []string{}
+		}
+
+		if _, err := qtx.EnsureCustomField(ctx, models.EnsureCustomFieldParams{
+			Name:    customField.Name,
+			Kind:    customField.Kind,
+			Options: options,
+
+			Namespace: namespace,
+		}); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	var (
+		attachmentIDMapLock sync.Mutex
+		attachmentIDMap     = map[int32]int32{}
+		blobKeys            = []string{}
+	)
+
+	createAttachment = func(attachment models.ExportedAttachment) error {
+		journalEntryIDMapLock.Lock()
+		defer journalEntryIDMapLock.Unlock()
+
+		if !attachment.JournalEntryID.Valid {
+			return ErrJournalEntryDoesNotExist
+		}
+
+		actualJournalEntryID, ok := journalEntryIDMap[attachment.JournalEntryID.Int32]
+		if !ok {
+			return ErrJournalEntryDoesNotExist
+		}
+
+		id, blobKey, err := p.createAttachment(
+			ctx,
+			qtx,
+
+			attachment.Name,
+			attachment.ContentType,
+			attachment.Data,
+
+			actualJournalEntryID,
+			namespace,
+		)
+		if err != nil {
+			return err
+		}
+
+		attachmentIDMapLock.Lock()
+		defer attachmentIDMapLock.Unlock()
+
+		attachmentIDMap[attachment.ID] = id
+		blobKeys = append(blobKeys, blobKey)
+
+		return nil
+	}
+
+	commit = func() error {
+		// The attachments and journal entries got new IDs, so the references to them in the imported journal entries have
+		// to be updated. Mentions can only be resolved now since the contacts are imported after the journal entries.
+		for _, journalEntryID := range journalEntryIDMap {
+			journalEntry, err := qtx.GetJournalEntry(ctx, models.GetJournalEntryParams{
+				ID:        journalEntryID,
+				Namespace: namespace,
+			})
+			if err != nil {
+				return err
+			}
+
+			oldBody, err := c.open(journalEncryptionFieldBody, journalEntry.Body)
+			if err != nil {
+				return err
+			}
+
+			body := replaceJournalEntryReferences(replaceAttachmentReferences(oldBody, attachmentIDMap), journalEntryIDMap)
+			if body != oldBody {
+				sealedBody, err := c.seal(journalEncryptionFieldBody, body)
+				if err != nil {
+					return err
+				}
+
+				if err := qtx.UpdateJournalEntryBody(ctx, models.UpdateJournalEntryBodyParams{
+					ID:        journalEntryID,
+					Namespace: namespace,
+					Body:      sealedBody,
+				}); err != nil {
+					return err
+				}
+			}
+
+			if err := setJournalEntryMentions(ctx, qtx, body, journalEntryID, namespace); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+
+		blobKeys = nil
+
+		return nil
+	}
+
+	rollback = func() error {
+		// Blobs are created outside of the transaction, so they have to be removed separately
+		if err := p.deleteBlobs(ctx, blobKeys); err != nil {
+			return err
+		}
+
+		blobKeys = nil
+
+		return tx.Rollback()
+	}
 
 	return
 }
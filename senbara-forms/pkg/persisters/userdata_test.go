@@ -0,0 +1,161 @@
+package persisters
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/migrations"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+)
+
+var (
+	createTableRegexp        = regexp.MustCompile(`(?s)create table (\w+) \((.*?)\);`)
+	foreignKeyRegexp         = regexp.MustCompile(`references (\w+) \(`)
+	deleteForNamespaceRegexp = regexp.MustCompile(`^Delete(\w+)ForNamespace$`)
+	camelCaseBoundaryRegexp  = regexp.MustCompile(`([a-z])([A-Z])`)
+)
+
+// getForeignKeys returns the tables that reference each table according to the migrations
+func getForeignKeys(t *testing.T) map[string][]string {
+	t.Helper()
+
+	referencingTables := map[string][]string{}
+	if err := fs.WalkDir(migrations.FS, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".sql") {
+			return err
+		}
+
+		migration, err := fs.ReadFile(migrations.FS, path)
+		if err != nil {
+			return err
+		}
+
+		up, _, _ := strings.Cut(string(migration), "-- +goose Down")
+		for _, table := range createTableRegexp.FindAllStringSubmatch(up, -1) {
+			for _, foreignKey := range foreignKeyRegexp.FindAllStringSubmatch(table[2], -1) {
+				referencingTables[foreignKey[1]] = append(referencingTables[foreignKey[1]], table[1])
+			}
+		}
+
+		return nil
+	}); err != nil {
+		t.Fatalf("could not read migrations: %v", err)
+	}
+
+	return referencingTables
+}
+
+func TestDeleteUserDataRespectsForeignKeys(t *testing.T) {
+	referencingTables := getForeignKeys(t)
+	if len(referencingTables["contacts"]) == 0 {
+		t.Fatal("expected tables to reference contacts")
+	}
+
+	// The namespace has rows in every table, including a journal entry that mentions a contact
+	deletedTables := map[string]bool{}
+	p, _ := newFakePersister(t, func(name string, args []driver.Value) (fakeResult, bool, error) {
+		match := deleteForNamespaceRegexp.FindStringSubmatch(name)
+		if match == nil {
+			return fakeResult{}, false, nil
+		}
+
+		table := strings.ToLower(camelCaseBoundaryRegexp.ReplaceAllString(match[1], "${1}_${2}"))
+		for _, referencingTable := range referencingTables[table] {
+			if !deletedTables[referencingTable] {
+				return fakeResult{}, false, fmt.Errorf("could not delete from %v: still referenced by %v", table, referencingTable)
+			}
+		}
+
+		deletedTables[table] = true
+
+		return fakeResult{}, false, nil
+	})
+
+	if err := p.DeleteUserData(context.Background(), "jane@example.com"); err != nil {
+		t.Fatalf("could not delete user data: %v", err)
+	}
+
+	for _, table := range []string{"contacts", "journal_entries", "journal_entry_mentions"} {
+		if !deletedTables[table] {
+			t.Errorf("expected %v to be deleted", table)
+		}
+	}
+}
+
+func TestCreateUserDataResolvesContactIDs(t *testing.T) {
+	p, db := newFakePersister(t, nil)
+
+	createJournalEntry,
+		createContact,
+		createDebt,
+		createActivity,
+		_,
+		_,
+		_,
+		_,
+
+		_,
+		rollback,
+
+		err := p.CreateUserData(context.Background(), nil, "jane@example.com")
+	if err != nil {
+		t.Fatalf("could not start import: %v", err)
+	}
+	defer rollback()
+
+	// IDs in an export are only unique per entity, so a journal entry and a contact can have the same one
+	if err := createJournalEntry(models.ExportedJournalEntry{ID: 1}); err != nil {
+		t.Fatalf("could not create journal entry: %v", err)
+	}
+
+	if err := createJournalEntry(models.ExportedJournalEntry{ID: 2}); err != nil {
+		t.Fatalf("could not create journal entry: %v", err)
+	}
+
+	if err := createContact(models.ExportedContact{ID: 1}); err != nil {
+		t.Fatalf("could not create contact: %v", err)
+	}
+
+	contacts := db.calls("CreateContact")
+	if len(contacts) != 1 {
+		t.Fatalf("expected one contact to be created, got %v", len(contacts))
+	}
+	contactID := contacts[0].id
+
+	exportedContactID := sql.NullInt32{Int32: 1, Valid: true}
+	if err := createDebt(models.ExportedDebt{ContactID: exportedContactID}); err != nil {
+		t.Fatalf("could not create debt: %v", err)
+	}
+
+	if err := createActivity(models.ExportedActivity{ContactID: exportedContactID}); err != nil {
+		t.Fatalf("could not create activity: %v", err)
+	}
+
+	for _, name := range []string{"CreateDebt", "CreateActivity"} {
+		calls := db.calls(name)
+		if len(calls) != 1 {
+			t.Fatalf("expected one call of %v, got %v", name, len(calls))
+		}
+
+		if calls[0].args[0] != contactID {
+			t.Errorf("%v used contact ID %v, want %v", name, calls[0].args[0], contactID)
+		}
+	}
+
+	// The exported ID 2 only belongs to a journal entry
+	missingContactID := sql.NullInt32{Int32: 2, Valid: true}
+	if err := createDebt(models.ExportedDebt{ContactID: missingContactID}); !errors.Is(err, ErrContactDoesNotExist) {
+		t.Errorf("createDebt() error = %v, want %v", err, ErrContactDoesNotExist)
+	}
+
+	if err := createActivity(models.ExportedActivity{ContactID: missingContactID}); !errors.Is(err, ErrContactDoesNotExist) {
+		t.Errorf("createActivity() error = %v, want %v", err, ErrContactDoesNotExist)
+	}
+}
@@ -50,6 +50,7 @@ select activities.id as activity_id,
     activities.name,
     activities.date,
     activities.description,
+    activities.version,
     contacts.id as contact_id,
     contacts.first_name,
     contacts.last_name
@@ -58,16 +59,18 @@ from contacts
 where contacts.id = $1
     and contacts.namespace = $2
     and activities.id = $3;
--- name: UpdateActivity :exec
+-- name: UpdateActivity :execrows
 update activities
-set name = $4,
-    date = $5,
-    description = $6
+set name = sqlc.arg(name),
+    date = sqlc.arg(date),
+    description = sqlc.arg(description),
+    version = activities.version + 1
 from contacts
-where contacts.id = $1
-    and contacts.namespace = $2
-    and activities.id = $3
-    and activities.contact_id = contacts.id;
+where contacts.id = sqlc.arg(contact_id)
+    and contacts.namespace = sqlc.arg(namespace)
+    and activities.id = sqlc.arg(id)
+    and activities.contact_id = contacts.id
+    and activities.version = sqlc.arg(version);
 -- name: GetActivitiesExportForNamespace :many
 select 'activites' as table_name,
     activities.id,
@@ -81,4 +84,21 @@ where contacts.namespace = $1;
 -- name: DeleteActivitiesForNamespace :exec
 delete from activities using contacts
 where activities.contact_id = contacts.id
-    and contacts.namespace = $1;
\ No newline at end of file
+    and contacts.namespace = $1;
+-- name: MoveActivitiesToContact :exec
+update activities
+set contact_id = $1
+from contacts
+where activities.contact_id = contacts.id
+    and contacts.id = $2
+    and contacts.namespace = $3;
+-- name: RestoreActivity :execrows
+insert into activities (id, name, date, description, contact_id)
+select sqlc.arg(id),
+    sqlc.arg(name),
+    sqlc.arg(date),
+    sqlc.arg(description),
+    contacts.id
+from contacts
+where contacts.id = sqlc.arg(contact_id)
+    and contacts.namespace = sqlc.arg(namespace);
\ No newline at end of file
@@ -0,0 +1,83 @@
+-- name: CreateAttachment :one
+with journal_entry as (
+    select id
+    from journal_entries
+    where journal_entries.id = $1
+        and namespace = $2
+),
+insertion as (
+    insert into attachments (
+            journal_entry_id,
+            name,
+            content_type,
+            size,
+            blob_key,
+            namespace
+        )
+    select $1,
+        $3,
+        $4,
+        $5,
+        $6,
+        $2
+    from journal_entry
+    returning attachments.id
+)
+select id
+from insertion;
+-- name: GetAttachment :one
+select *
+from attachments
+where id = $1
+    and namespace = $2;
+-- name: GetAttachments :many
+select *
+from attachments
+where journal_entry_id = $1
+    and namespace = $2
+order by created_at asc,
+    id asc;
+-- name: DeleteAttachment :one
+delete from attachments
+where id = $1
+    and namespace = $2
+returning blob_key;
+-- name: DeleteAttachmentsForJournalEntry :many
+delete from attachments
+where journal_entry_id = $1
+    and namespace = $2
+returning blob_key;
+-- name: DeleteAttachmentsForNamespace :many
+delete from attachments
+where namespace = $1
+returning blob_key;
+-- name: GetAttachmentsExportForNamespace :many
+select 'attachments' as table_name,
+    id,
+    journal_entry_id,
+    name,
+    content_type,
+    blob_key
+from attachments
+where namespace = $1
+order by id asc;
+-- name: RestoreAttachment :execrows
+insert into attachments (
+        id,
+        journal_entry_id,
+        name,
+        content_type,
+        size,
+        blob_key,
+        namespace
+    )
+select sqlc.arg(id),
+    journal_entries.id,
+    sqlc.arg(name),
+    sqlc.arg(content_type),
+    sqlc.arg(size),
+    sqlc.arg(blob_key),
+    journal_entries.namespace
+from journal_entries
+where journal_entries.id = sqlc.arg(journal_entry_id)
+    and journal_entries.namespace = sqlc.arg(namespace);
\ No newline at end of file
@@ -0,0 +1,41 @@
+-- name: GetNamespaces :many
+select namespace
+from journal_entries
+union
+select namespace
+from contacts
+union
+select namespace
+from custom_fields
+union
+select namespace
+from settings
+order by namespace;
+-- name: GetBackupRun :one
+select *
+from backup_runs
+where namespace = $1;
+-- name: UpdateBackupRun :exec
+insert into backup_runs (
+        namespace,
+        ran_at,
+        file_name,
+        size,
+        error,
+        last_succeeded_at,
+        journal_skipped
+    )
+values ($1, $2, $3, $4, $5, $6, $7) on conflict (namespace) do
+update
+set ran_at = excluded.ran_at,
+    file_name = excluded.file_name,
+    size = excluded.size,
+    error = excluded.error,
+    journal_skipped = excluded.journal_skipped,
+    last_succeeded_at = coalesce(
+        excluded.last_succeeded_at,
+        backup_runs.last_succeeded_at
+    );
+-- name: DeleteBackupRunsForNamespace :exec
+delete from backup_runs
+where namespace = $1;
\ No newline at end of file
@@ -0,0 +1,12 @@
+-- name: CreateLargeObject :one
+select lo_from_bytea(0, sqlc.arg(data)::bytea)::bigint as oid;
+-- name: GetLargeObject :one
+select lo_get(sqlc.arg(oid)::bigint::oid) as data;
+-- name: GetLargeObjectChunk :one
+select lo_get(
+        sqlc.arg(oid)::bigint::oid,
+        sqlc.arg(chunk_offset)::bigint,
+        sqlc.arg(chunk_length)::integer
+    ) as data;
+-- name: DeleteLargeObject :exec
+select lo_unlink(sqlc.arg(oid)::bigint::oid);
\ No newline at end of file
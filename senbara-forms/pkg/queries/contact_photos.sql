@@ -0,0 +1,72 @@
+-- name: CreateContactPhoto :one
+with contact as (
+    select id
+    from contacts
+    where contacts.id = $1
+        and namespace = $2
+),
+insertion as (
+    insert into contact_photos (contact_id, size, content_type, data, namespace)
+    select $1,
+        $3,
+        $4,
+        $5,
+        $2
+    from contact
+    where exists (
+            select 1
+            from contact
+        )
+    returning contact_photos.id
+)
+select id
+from insertion;
+-- name: GetContactPhoto :one
+select id,
+    content_type,
+    data,
+    created_at
+from contact_photos
+where contact_id = $1
+    and namespace = $2
+    and size = $3;
+-- name: GetContactPhotoSizes :many
+select size
+from contact_photos
+where contact_id = $1
+    and namespace = $2
+order by size asc;
+-- name: DeleteContactPhotos :exec
+delete from contact_photos
+where contact_id = $1
+    and namespace = $2;
+-- name: GetContactPhotosExportForNamespace :many
+select 'contact_photos' as table_name,
+    id,
+    contact_id,
+    size,
+    content_type,
+    data
+from contact_photos
+where namespace = $1;
+-- name: DeleteContactPhotosForNamespace :exec
+delete from contact_photos
+where namespace = $1;
+-- name: MoveContactPhotosToContact :exec
+update contact_photos
+set contact_id = $1
+where contact_id = $2
+    and namespace = $3
+    and not exists (
+        select 1
+        from contact_photos as existing_photos
+        where existing_photos.contact_id = $1
+    );
+-- name: GetContactPhotos :many
+select id,
+    size,
+    content_type,
+    data
+from contact_photos
+where contact_id = $1
+    and namespace = $2;
\ No newline at end of file
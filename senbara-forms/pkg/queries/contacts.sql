@@ -23,18 +23,25 @@ select *
 from contacts
 where id = $1
     and namespace = $2;
--- name: UpdateContact :exec
+-- name: UpdateContact :execrows
 update contacts
-set first_name = $3,
-    last_name = $4,
-    nickname = $5,
-    email = $6,
-    pronouns = $7,
-    birthday = $8,
-    address = $9,
-    notes = $10
-where id = $1
-    and namespace = $2;
+set first_name = sqlc.arg(first_name),
+    last_name = sqlc.arg(last_name),
+    nickname = sqlc.arg(nickname),
+    email = sqlc.arg(email),
+    pronouns = sqlc.arg(pronouns),
+    birthday = sqlc.arg(birthday),
+    address = sqlc.arg(address),
+    notes = sqlc.arg(notes),
+    contact_frequency = sqlc.arg(contact_frequency),
+    updated_at = now(),
+    version = version + 1
+where id = sqlc.arg(id)
+    and namespace = sqlc.arg(namespace)
+    and (
+        sqlc.narg(version)::integer is null
+        or version = sqlc.narg(version)
+    );
 -- name: DeleteContactsForNamespace :exec
 delete from contacts
 where namespace = $1;
@@ -43,4 +50,105 @@ select 'contacts' as table_name,
     *
 from contacts
 where namespace = $1
-order by first_name desc;
\ No newline at end of file
+order by first_name desc;
+-- name: GetOverdueContacts :many
+select contacts.id,
+    contacts.first_name,
+    contacts.last_name,
+    contacts.contact_frequency,
+    contact_statuses.last_contacted
+from contacts
+    join contact_statuses on contact_statuses.contact_id = contacts.id
+where contacts.namespace = $1
+    and contact_statuses.overdue
+order by contact_statuses.last_contacted asc nulls first;
+-- name: GetContactsByNameAsc :many
+select *
+from contacts
+where namespace = sqlc.arg(namespace)
+    and (
+        sqlc.arg(after_id)::integer = 0
+        or (first_name, last_name, id) > (
+            sqlc.arg(after_first_name)::text,
+            sqlc.arg(after_last_name)::text,
+            sqlc.arg(after_id)::integer
+        )
+    )
+order by first_name asc,
+    last_name asc,
+    id asc
+limit sqlc.arg(row_limit);
+-- name: GetContactsByNameDesc :many
+select *
+from contacts
+where namespace = sqlc.arg(namespace)
+    and (
+        sqlc.arg(after_id)::integer = 0
+        or (first_name, last_name, id) < (
+            sqlc.arg(after_first_name)::text,
+            sqlc.arg(after_last_name)::text,
+            sqlc.arg(after_id)::integer
+        )
+    )
+order by first_name desc,
+    last_name desc,
+    id desc
+limit sqlc.arg(row_limit);
+-- name: GetContactsByUpdatedAtAsc :many
+select *
+from contacts
+where namespace = sqlc.arg(namespace)
+    and (
+        sqlc.arg(after_id)::integer = 0
+        or (updated_at, id) > (
+            sqlc.arg(after_updated_at)::timestamp,
+            sqlc.arg(after_id)::integer
+        )
+    )
+order by updated_at asc,
+    id asc
+limit sqlc.arg(row_limit);
+-- name: GetContactsByUpdatedAtDesc :many
+select *
+from contacts
+where namespace = sqlc.arg(namespace)
+    and (
+        sqlc.arg(after_id)::integer = 0
+        or (updated_at, id) < (
+            sqlc.arg(after_updated_at)::timestamp,
+            sqlc.arg(after_id)::integer
+        )
+    )
+order by updated_at desc,
+    id desc
+limit sqlc.arg(row_limit);
+-- name: GetContactIDByName :one
+select id
+from contacts
+where namespace = sqlc.arg(namespace)
+    and (
+        lower(trim(first_name || ' ' || last_name)) = lower(sqlc.arg(name)::text)
+        or lower(nickname) = lower(sqlc.arg(name)::text)
+    )
+order by id asc
+limit 1;
+-- name: RestoreContact :exec
+insert into contacts (
+        id,
+        first_name,
+        last_name,
+        nickname,
+        email,
+        pronouns,
+        namespace,
+        birthday,
+        address,
+        notes,
+        contact_frequency
+    )
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);
+-- name: GetContactStatus :one
+select *
+from contact_statuses
+where contact_id = $1
+    and namespace = $2;
\ No newline at end of file
@@ -0,0 +1,88 @@
+-- name: CreateCustomField :one
+insert into custom_fields (name, kind, options, namespace)
+values ($1, $2, $3, $4)
+returning id;
+-- name: EnsureCustomField :one
+insert into custom_fields (name, kind, options, namespace)
+values ($1, $2, $3, $4) on conflict (namespace, name) do
+update
+set name = excluded.name
+returning id;
+-- name: GetCustomFields :many
+select *
+from custom_fields
+where namespace = $1
+order by name asc;
+-- name: DeleteCustomField :exec
+delete from custom_fields
+where id = $1
+    and namespace = $2;
+-- name: GetCustomFieldValues :many
+select custom_fields.id as custom_field_id,
+    custom_fields.name,
+    custom_fields.kind,
+    custom_field_values.value
+from contacts
+    inner join custom_field_values on custom_field_values.contact_id = contacts.id
+    inner join custom_fields on custom_fields.id = custom_field_values.custom_field_id
+where contacts.id = $1
+    and contacts.namespace = $2
+order by custom_fields.name asc;
+-- name: CreateCustomFieldValue :one
+insert into custom_field_values (contact_id, custom_field_id, value)
+select contacts.id,
+    custom_fields.id,
+    $3
+from contacts,
+    custom_fields
+where contacts.id = $1
+    and custom_fields.id = $2
+    and contacts.namespace = $4
+    and custom_fields.namespace = $4
+returning custom_field_values.id;
+-- name: MoveCustomFieldValuesToContact :exec
+update custom_field_values
+set contact_id = $1
+from contacts
+where custom_field_values.contact_id = contacts.id
+    and contacts.id = $2
+    and contacts.namespace = $3
+    and custom_field_values.custom_field_id not in (
+        select existing_custom_field_values.custom_field_id
+        from custom_field_values as existing_custom_field_values
+        where existing_custom_field_values.contact_id = $1
+    );
+-- name: DeleteCustomFieldValuesForContact :exec
+delete from custom_field_values using contacts
+where custom_field_values.contact_id = contacts.id
+    and contacts.id = $1
+    and contacts.namespace = $2;
+-- name: DeleteCustomFieldValuesForCustomField :exec
+delete from custom_field_values using custom_fields
+where custom_field_values.custom_field_id = custom_fields.id
+    and custom_fields.id = $1
+    and custom_fields.namespace = $2;
+-- name: DeleteCustomFieldValuesForNamespace :exec
+delete from custom_field_values using custom_fields
+where custom_field_values.custom_field_id = custom_fields.id
+    and custom_fields.namespace = $1;
+-- name: DeleteCustomFieldsForNamespace :exec
+delete from custom_fields
+where namespace = $1;
+-- name: GetCustomFieldsExportForNamespace :many
+select 'custom_fields' as table_name,
+    id,
+    name,
+    kind,
+    options
+from custom_fields
+where namespace = $1
+order by id asc;
+-- name: GetCustomFieldValuesExportForNamespace :many
+select 'custom_field_values' as table_name,
+    custom_field_values.contact_id,
+    custom_fields.name,
+    custom_field_values.value
+from custom_field_values
+    inner join custom_fields on custom_fields.id = custom_field_values.custom_field_id
+where custom_fields.namespace = $1;
\ No newline at end of file
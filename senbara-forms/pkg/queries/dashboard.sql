@@ -0,0 +1,48 @@
+-- name: GetRecentJournalEntries :many
+select id,
+    title,
+    date,
+    rating
+from journal_entries
+where namespace = $1
+order by date desc
+limit $2;
+-- name: GetDebtBalances :many
+select debts.currency,
+    sum(debts.amount)::double precision as balance
+from debts
+    inner join contacts on contacts.id = debts.contact_id
+where contacts.namespace = $1
+group by debts.currency
+order by debts.currency asc;
+-- name: GetUpcomingActivities :many
+select activities.id,
+    activities.name,
+    activities.date,
+    contacts.id as contact_id,
+    contacts.first_name,
+    contacts.last_name
+from activities
+    inner join contacts on contacts.id = activities.contact_id
+where contacts.namespace = $1
+    and activities.date >= now()
+order by activities.date asc
+limit $2;
+-- name: GetRecentlyUpdatedContacts :many
+select id,
+    first_name,
+    last_name,
+    updated_at
+from contacts
+where namespace = $1
+order by updated_at desc
+limit $2;
+-- name: GetUpcomingBirthdays :many
+select *
+from contacts
+where namespace = sqlc.arg(namespace)
+    and birthday is not null
+    and get_next_birthday(birthday, sqlc.arg(today)::date) < sqlc.arg(today)::date + sqlc.arg(days)::integer
+order by get_next_birthday(birthday, sqlc.arg(today)::date) asc,
+    id asc
+limit sqlc.arg(row_limit);
\ No newline at end of file
@@ -45,6 +45,7 @@ select debts.id as debt_id,
     debts.amount,
     debts.currency,
     debts.description,
+    debts.version,
     contacts.id as contact_id,
     contacts.first_name,
     contacts.last_name
@@ -53,16 +54,18 @@ from contacts
 where contacts.id = $1
     and contacts.namespace = $2
     and debts.id = $3;
--- name: UpdateDebt :exec
+-- name: UpdateDebt :execrows
 update debts
-set amount = $4,
-    currency = $5,
-    description = $6
+set amount = sqlc.arg(amount),
+    currency = sqlc.arg(currency),
+    description = sqlc.arg(description),
+    version = debts.version + 1
 from contacts
-where contacts.id = $1
-    and contacts.namespace = $2
-    and debts.id = $3
-    and debts.contact_id = contacts.id;
+where contacts.id = sqlc.arg(contact_id)
+    and contacts.namespace = sqlc.arg(namespace)
+    and debts.id = sqlc.arg(id)
+    and debts.contact_id = contacts.id
+    and debts.version = sqlc.arg(version);
 -- name: GetDebtsExportForNamespace :many
 select 'debts' as table_name,
     debts.id,
@@ -76,4 +79,21 @@ where contacts.namespace = $1;
 -- name: DeleteDebtsForNamespace :exec
 delete from debts using contacts
 where debts.contact_id = contacts.id
-    and contacts.namespace = $1;
\ No newline at end of file
+    and contacts.namespace = $1;
+-- name: MoveDebtsToContact :exec
+update debts
+set contact_id = $1
+from contacts
+where debts.contact_id = contacts.id
+    and contacts.id = $2
+    and contacts.namespace = $3;
+-- name: RestoreDebt :execrows
+insert into debts (id, amount, currency, description, contact_id)
+select sqlc.arg(id),
+    sqlc.arg(amount),
+    sqlc.arg(currency),
+    sqlc.arg(description),
+    contacts.id
+from contacts
+where contacts.id = sqlc.arg(contact_id)
+    and contacts.namespace = sqlc.arg(namespace);
\ No newline at end of file
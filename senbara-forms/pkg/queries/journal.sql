@@ -9,20 +9,26 @@ from journal_entries
 where id = $1
     and namespace = $2;
 -- name: CreateJournalEntry :one
-insert into journal_entries (title, body, rating, namespace)
-values ($1, $2, $3, $4)
+insert into journal_entries (title, date, body, rating, namespace)
+values ($1, $2, $3, $4, $5)
 returning id;
 -- name: DeleteJournalEntry :exec
 delete from journal_entries
 where id = $1
     and namespace = $2;
--- name: UpdateJournalEntry :exec
+-- name: UpdateJournalEntry :execrows
 update journal_entries
-set title = $3,
-    body = $4,
-    rating = $5
-where id = $1
-    and namespace = $2;
+set title = sqlc.arg(title),
+    body = sqlc.arg(body),
+    rating = sqlc.arg(rating),
+    date = sqlc.arg(date),
+    version = version + 1
+where id = sqlc.arg(id)
+    and namespace = sqlc.arg(namespace)
+    and (
+        sqlc.narg(version)::integer is null
+        or version = sqlc.narg(version)
+    );
 -- name: DeleteJournalEntriesForNamespace :exec
 delete from journal_entries
 where namespace = $1;
@@ -31,4 +37,118 @@ select 'journal_entries' as table_name,
     *
 from journal_entries
 where namespace = $1
-order by date desc;
\ No newline at end of file
+order by date desc;
+-- name: GetJournalEntriesByDateAsc :many
+select *
+from journal_entries
+where namespace = sqlc.arg(namespace)
+    and (
+        cardinality(sqlc.arg(tags)::text []) = 0
+        or (
+            select count(*)
+            from journal_entry_tags
+            where journal_entry_tags.journal_entry_id = journal_entries.id
+                and journal_entry_tags.name = any(sqlc.arg(tags)::text [])
+        ) = cardinality(sqlc.arg(tags)::text [])
+    )
+    and (
+        sqlc.arg(after_id)::integer = 0
+        or (date, id) > (
+            sqlc.arg(after_date)::timestamp,
+            sqlc.arg(after_id)::integer
+        )
+    )
+order by date asc,
+    id asc
+limit sqlc.arg(row_limit);
+-- name: GetJournalEntriesByDateDesc :many
+select *
+from journal_entries
+where namespace = sqlc.arg(namespace)
+    and (
+        cardinality(sqlc.arg(tags)::text []) = 0
+        or (
+            select count(*)
+            from journal_entry_tags
+            where journal_entry_tags.journal_entry_id = journal_entries.id
+                and journal_entry_tags.name = any(sqlc.arg(tags)::text [])
+        ) = cardinality(sqlc.arg(tags)::text [])
+    )
+    and (
+        sqlc.arg(after_id)::integer = 0
+        or (date, id) < (
+            sqlc.arg(after_date)::timestamp,
+            sqlc.arg(after_id)::integer
+        )
+    )
+order by date desc,
+    id desc
+limit sqlc.arg(row_limit);
+-- name: GetJournalEntriesByRatingAsc :many
+select *
+from journal_entries
+where namespace = sqlc.arg(namespace)
+    and (
+        cardinality(sqlc.arg(tags)::text []) = 0
+        or (
+            select count(*)
+            from journal_entry_tags
+            where journal_entry_tags.journal_entry_id = journal_entries.id
+                and journal_entry_tags.name = any(sqlc.arg(tags)::text [])
+        ) = cardinality(sqlc.arg(tags)::text [])
+    )
+    and (
+        sqlc.arg(after_id)::integer = 0
+        or (rating, id) > (
+            sqlc.arg(after_rating)::integer,
+            sqlc.arg(after_id)::integer
+        )
+    )
+order by rating asc,
+    id asc
+limit sqlc.arg(row_limit);
+-- name: GetJournalEntriesByRatingDesc :many
+select *
+from journal_entries
+where namespace = sqlc.arg(namespace)
+    and (
+        cardinality(sqlc.arg(tags)::text []) = 0
+        or (
+            select count(*)
+            from journal_entry_tags
+            where journal_entry_tags.journal_entry_id = journal_entries.id
+                and journal_entry_tags.name = any(sqlc.arg(tags)::text [])
+        ) = cardinality(sqlc.arg(tags)::text [])
+    )
+    and (
+        sqlc.arg(after_id)::integer = 0
+        or (rating, id) < (
+            sqlc.arg(after_rating)::integer,
+            sqlc.arg(after_id)::integer
+        )
+    )
+order by rating desc,
+    id desc
+limit sqlc.arg(row_limit);
+-- name: GetJournalEntryRatings :many
+select date,
+    rating
+from journal_entries
+where namespace = $1
+order by date;
+-- name: UpdateJournalEntryBody :exec
+update journal_entries
+set body = $3,
+    version = version + 1
+where id = $1
+    and namespace = $2;
+-- name: UpdateJournalEntryTitleAndBody :exec
+update journal_entries
+set title = $3,
+    body = $4,
+    version = version + 1
+where id = $1
+    and namespace = $2;
+-- name: RestoreJournalEntry :exec
+insert into journal_entries (id, title, date, body, rating, namespace)
+values ($1, $2, $3, $4, $5, $6);
\ No newline at end of file
@@ -0,0 +1,58 @@
+-- name: CreateJournalEntryMentions :exec
+insert into journal_entry_mentions (journal_entry_id, contact_id)
+select journal_entries.id,
+    contacts.id
+from journal_entries,
+    contacts
+where journal_entries.id = sqlc.arg(id)
+    and journal_entries.namespace = sqlc.arg(namespace)
+    and contacts.namespace = sqlc.arg(namespace)
+    and (
+        lower(trim(contacts.first_name || ' ' || contacts.last_name)) = any(sqlc.arg(names)::text [])
+        or lower(contacts.nickname) = any(sqlc.arg(names)::text [])
+    ) on conflict do nothing;
+-- name: DeleteJournalEntryMentions :exec
+delete from journal_entry_mentions using journal_entries
+where journal_entry_mentions.journal_entry_id = journal_entries.id
+    and journal_entries.id = $1
+    and journal_entries.namespace = $2;
+-- name: DeleteJournalEntryMentionsForContact :exec
+delete from journal_entry_mentions using contacts
+where journal_entry_mentions.contact_id = contacts.id
+    and contacts.id = $1
+    and contacts.namespace = $2;
+-- name: DeleteJournalEntryMentionsForNamespace :exec
+delete from journal_entry_mentions using journal_entries
+where journal_entry_mentions.journal_entry_id = journal_entries.id
+    and journal_entries.namespace = $1;
+-- name: MoveJournalEntryMentionsToContact :exec
+insert into journal_entry_mentions (journal_entry_id, contact_id)
+select journal_entry_mentions.journal_entry_id,
+    contacts.id
+from journal_entry_mentions
+    inner join contacts as other_contacts on other_contacts.id = journal_entry_mentions.contact_id,
+    contacts
+where contacts.id = $1
+    and other_contacts.id = $2
+    and contacts.namespace = $3
+    and other_contacts.namespace = $3 on conflict do nothing;
+-- name: GetJournalEntriesMentioningContact :many
+select journal_entries.id,
+    journal_entries.title,
+    journal_entries.date
+from journal_entry_mentions
+    inner join journal_entries on journal_entries.id = journal_entry_mentions.journal_entry_id
+    inner join contacts on contacts.id = journal_entry_mentions.contact_id
+where contacts.id = $1
+    and contacts.namespace = $2
+order by journal_entries.date desc;
+-- name: CreateJournalEntryMentionsForContact :exec
+insert into journal_entry_mentions (journal_entry_id, contact_id)
+select journal_entries.id,
+    contacts.id
+from journal_entries,
+    contacts
+where contacts.id = sqlc.arg(id)
+    and contacts.namespace = sqlc.arg(namespace)
+    and journal_entries.namespace = sqlc.arg(namespace)
+    and journal_entries.id = any(sqlc.arg(journal_entry_ids)::integer []) on conflict do nothing;
\ No newline at end of file
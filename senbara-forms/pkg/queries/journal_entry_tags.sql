@@ -0,0 +1,46 @@
+-- name: CreateJournalEntryTag :exec
+insert into journal_entry_tags (journal_entry_id, name)
+select journal_entries.id,
+    $3
+from journal_entries
+where journal_entries.id = $1
+    and journal_entries.namespace = $2 on conflict do nothing;
+-- name: DeleteJournalEntryTags :exec
+delete from journal_entry_tags using journal_entries
+where journal_entry_tags.journal_entry_id = journal_entries.id
+    and journal_entries.id = $1
+    and journal_entries.namespace = $2;
+-- name: DeleteJournalEntryTagsForNamespace :exec
+delete from journal_entry_tags using journal_entries
+where journal_entry_tags.journal_entry_id = journal_entries.id
+    and journal_entries.namespace = $1;
+-- name: GetJournalEntryTags :many
+select journal_entry_tags.name
+from journal_entry_tags
+    inner join journal_entries on journal_entries.id = journal_entry_tags.journal_entry_id
+where journal_entries.id = $1
+    and journal_entries.namespace = $2
+order by journal_entry_tags.name asc;
+-- name: GetJournalEntryTagsForJournalEntries :many
+select journal_entry_tags.journal_entry_id,
+    journal_entry_tags.name
+from journal_entry_tags
+    inner join journal_entries on journal_entries.id = journal_entry_tags.journal_entry_id
+where journal_entries.namespace = sqlc.arg(namespace)
+    and journal_entries.id = any(sqlc.arg(ids)::integer [])
+order by journal_entry_tags.name asc;
+-- name: GetJournalEntryTagsExportForNamespace :many
+select journal_entry_tags.journal_entry_id,
+    journal_entry_tags.name
+from journal_entry_tags
+    inner join journal_entries on journal_entries.id = journal_entry_tags.journal_entry_id
+where journal_entries.namespace = $1
+order by journal_entry_tags.name asc;
+-- name: GetJournalTags :many
+select journal_entry_tags.name,
+    count(*) as count
+from journal_entry_tags
+    inner join journal_entries on journal_entries.id = journal_entry_tags.journal_entry_id
+where journal_entries.namespace = $1
+group by journal_entry_tags.name
+order by journal_entry_tags.name asc;
\ No newline at end of file
@@ -0,0 +1,98 @@
+-- name: CreateRelationship :one
+insert into relationships (contact_id, related_contact_id, kind)
+select contacts.id,
+    related_contacts.id,
+    $3
+from contacts,
+    contacts as related_contacts
+where contacts.id = $1
+    and related_contacts.id = $2
+    and contacts.namespace = $4
+    and related_contacts.namespace = $4
+returning relationships.id;
+-- name: GetRelationships :many
+select relationships.id,
+    relationships.kind,
+    related_contacts.id as related_contact_id,
+    related_contacts.first_name,
+    related_contacts.last_name
+from contacts
+    inner join relationships on relationships.contact_id = contacts.id
+    inner join contacts as related_contacts on related_contacts.id = relationships.related_contact_id
+where contacts.id = $1
+    and contacts.namespace = $2
+order by related_contacts.first_name desc;
+-- name: GetRelationship :one
+select relationships.id,
+    relationships.contact_id,
+    relationships.related_contact_id,
+    relationships.kind
+from contacts
+    inner join relationships on relationships.contact_id = contacts.id
+where contacts.id = $1
+    and contacts.namespace = $2
+    and relationships.id = $3;
+-- name: DeleteRelationship :exec
+delete from relationships using contacts
+where relationships.contact_id = contacts.id
+    and contacts.id = $1
+    and contacts.namespace = $2
+    and relationships.related_contact_id = $3
+    and relationships.kind = $4;
+-- name: DeleteRelationshipsForContact :exec
+delete from relationships using contacts
+where (
+        relationships.contact_id = contacts.id
+        or relationships.related_contact_id = contacts.id
+    )
+    and contacts.id = $1
+    and contacts.namespace = $2;
+-- name: DeleteRelationshipsForNamespace :exec
+delete from relationships using contacts
+where relationships.contact_id = contacts.id
+    and contacts.namespace = $1;
+-- name: DeleteRelationshipsBetweenContacts :exec
+delete from relationships using contacts
+where relationships.contact_id = contacts.id
+    and (
+        (
+            relationships.contact_id = $1
+            and relationships.related_contact_id = $2
+        )
+        or (
+            relationships.contact_id = $2
+            and relationships.related_contact_id = $1
+        )
+    )
+    and contacts.namespace = $3;
+-- name: CopyRelationshipsToContact :exec
+insert into relationships (contact_id, related_contact_id, kind)
+select target_contacts.id,
+    relationships.related_contact_id,
+    relationships.kind
+from relationships
+    join contacts on relationships.contact_id = contacts.id
+    join contacts as target_contacts on target_contacts.namespace = contacts.namespace
+where target_contacts.id = $1
+    and relationships.contact_id = $2
+    and contacts.namespace = $3
+union all
+select relationships.contact_id,
+    target_contacts.id,
+    relationships.kind
+from relationships
+    join contacts on relationships.related_contact_id = contacts.id
+    join contacts as target_contacts on target_contacts.namespace = contacts.namespace
+where target_contacts.id = $1
+    and relationships.related_contact_id = $2
+    and contacts.namespace = $3 on conflict do nothing;
+-- name: GetRelationshipsExportForNamespace :many
+select 'relationships' as table_name,
+    relationships.id,
+    relationships.contact_id,
+    relationships.related_contact_id,
+    relationships.kind
+from contacts
+    inner join relationships on relationships.contact_id = contacts.id
+where contacts.namespace = $1
+order by relationships.id asc;
\ No newline at end of file
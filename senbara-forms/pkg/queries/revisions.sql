@@ -0,0 +1,50 @@
+-- name: CreateRevision :exec
+insert into revisions (entity_name, entity_id, data, namespace)
+values ($1, $2, $3, $4);
+-- name: GetRevisions :many
+select id,
+    data,
+    created_at
+from revisions
+where namespace = $1
+    and entity_name = $2
+    and entity_id = $3
+order by created_at desc,
+    id desc;
+-- name: GetRevision :one
+select *
+from revisions
+where id = $1
+    and namespace = $2;
+-- name: DeleteRevisionsForEntity :exec
+delete from revisions
+where namespace = $1
+    and entity_name = $2
+    and entity_id = $3;
+-- name: DeleteRevisionsForExpiredTrashItems :exec
+delete from revisions using trash_items
+where trash_items.deleted_at <= now() - interval '30 days'
+    and revisions.namespace = trash_items.namespace
+    and revisions.entity_name = trash_items.entity_name
+    and revisions.entity_id = trash_items.entity_id;
+-- name: DeleteRevisionsForTrashItem :exec
+delete from revisions using trash_items
+where trash_items.id = $1
+    and trash_items.namespace = $2
+    and revisions.namespace = trash_items.namespace
+    and revisions.entity_name = trash_items.entity_name
+    and revisions.entity_id = trash_items.entity_id;
+-- name: DeleteRevisionsForNamespace :exec
+delete from revisions
+where namespace = $1;
+-- name: GetRevisionsForEntityName :many
+select id,
+    data
+from revisions
+where namespace = $1
+    and entity_name = $2;
+-- name: UpdateRevisionData :exec
+update revisions
+set data = $3
+where id = $1
+    and namespace = $2;
\ No newline at end of file
@@ -0,0 +1,104 @@
+-- name: Search :many
+with search as (
+    select get_search_config(sqlc.arg(namespace)) as config,
+        websearch_to_tsquery(
+            get_search_config(sqlc.arg(namespace)),
+            sqlc.arg(query)
+        ) as query
+),
+results as (
+    select 'journalEntry' as entity_name,
+        journal_entries.id,
+        0 as contact_id,
+        journal_entries.title,
+        ts_headline(
+            search.config,
+            journal_entries.body,
+            search.query,
+            E'StartSel=\x01, StopSel=\x02'
+        ) as headline,
+        ts_rank(journal_entries.search_vector, search.query) as rank
+    from journal_entries,
+        search
+    where journal_entries.namespace = sqlc.arg(namespace)
+        and journal_entries.search_vector @@ search.query
+    union all
+    select 'contact' as entity_name,
+        contacts.id,
+        contacts.id as contact_id,
+        contacts.first_name || ' ' || contacts.last_name as title,
+        ts_headline(
+            search.config,
+            contacts.nickname || ' ' || contacts.notes,
+            search.query,
+            E'StartSel=\x01, StopSel=\x02'
+        ) as headline,
+        ts_rank(contacts.search_vector, search.query) as rank
+    from contacts,
+        search
+    where contacts.namespace = sqlc.arg(namespace)
+        and contacts.search_vector @@ search.query
+    union all
+    select 'debt' as entity_name,
+        debts.id,
+        contacts.id as contact_id,
+        contacts.first_name || ' ' || contacts.last_name as title,
+        ts_headline(
+            search.config,
+            debts.description,
+            search.query,
+            E'StartSel=\x01, StopSel=\x02'
+        ) as headline,
+        ts_rank(debts.search_vector, search.query) as rank
+    from debts
+        inner join contacts on contacts.id = debts.contact_id,
+        search
+    where contacts.namespace = sqlc.arg(namespace)
+        and debts.search_vector @@ search.query
+    union all
+    select 'activity' as entity_name,
+        activities.id,
+        contacts.id as contact_id,
+        activities.name as title,
+        ts_headline(
+            search.config,
+            activities.description,
+            search.query,
+            E'StartSel=\x01, StopSel=\x02'
+        ) as headline,
+        ts_rank(activities.search_vector, search.query) as rank
+    from activities
+        inner join contacts on contacts.id = activities.contact_id,
+        search
+    where contacts.namespace = sqlc.arg(namespace)
+        and activities.search_vector @@ search.query
+)
+select entity_name,
+    id,
+    contact_id,
+    title,
+    headline,
+    rank
+from results
+order by rank desc
+limit sqlc.arg(row_limit);
+-- name: ReindexJournalEntriesForNamespace :exec
+update journal_entries
+set search_vector = null
+where namespace = $1;
+-- name: ReindexContactsForNamespace :exec
+update contacts
+set search_vector = null
+where namespace = $1;
+-- name: ReindexDebtsForNamespace :exec
+update debts
+set search_vector = null
+from contacts
+where debts.contact_id = contacts.id
+    and contacts.namespace = $1;
+-- name: ReindexActivitiesForNamespace :exec
+update activities
+set search_vector = null
+from contacts
+where activities.contact_id = contacts.id
+    and contacts.namespace = $1;
\ No newline at end of file
@@ -0,0 +1,31 @@
+-- name: GetSettings :one
+select *
+from settings
+where namespace = $1;
+-- name: UpdateSettings :exec
+insert into settings (
+        namespace,
+        time_zone,
+        search_language,
+        preview_format
+    )
+values ($1, $2, $3, $4) on conflict (namespace) do
+update
+set time_zone = excluded.time_zone,
+    search_language = excluded.search_language,
+    preview_format = excluded.preview_format;
+-- name: DeleteSettingsForNamespace :exec
+delete from settings
+where namespace = $1;
+-- name: UpdateJournalEncryption :exec
+insert into settings (
+        namespace,
+        journal_encrypted,
+        journal_encryption_salt,
+        journal_encryption_check
+    )
+values ($1, $2, $3, $4) on conflict (namespace) do
+update
+set journal_encrypted = excluded.journal_encrypted,
+    journal_encryption_salt = excluded.journal_encryption_salt,
+    journal_encryption_check = excluded.journal_encryption_check;
\ No newline at end of file
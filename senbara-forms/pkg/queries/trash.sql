@@ -0,0 +1,51 @@
+-- name: CreateTrashItem :one
+insert into trash_items (entity_name, entity_id, title, data, namespace)
+values ($1, $2, $3, $4, $5)
+returning id;
+-- name: GetTrashItems :many
+select id,
+    entity_name,
+    title,
+    deleted_at
+from trash_items
+where namespace = $1
+    and deleted_at > now() - interval '30 days'
+order by deleted_at desc;
+-- name: GetTrashItem :one
+select *
+from trash_items
+where id = $1
+    and namespace = $2
+    and deleted_at > now() - interval '30 days';
+-- name: GetTrashItemForEntity :one
+select *
+from trash_items
+where namespace = $1
+    and entity_name = $2
+    and entity_id = $3
+    and deleted_at > now() - interval '30 days'
+order by deleted_at desc
+limit 1;
+-- name: DeleteTrashItem :exec
+delete from trash_items
+where id = $1
+    and namespace = $2;
+-- name: DeleteTrashItemsForNamespace :exec
+delete from trash_items
+where namespace = $1;
+-- name: PurgeTrashItems :execrows
+delete from trash_items
+where deleted_at <= now() - interval '30 days';
+-- name: GetTrashItemsForEntityName :many
+select id,
+    title,
+    data
+from trash_items
+where namespace = $1
+    and entity_name = $2;
+-- name: UpdateTrashItem :exec
+update trash_items
+set title = $3,
+    data = $4
+where id = $1
+    and namespace = $2;
\ No newline at end of file
@@ -206,7 +206,7 @@ func (q *Queries) GetActivitiesExportForNamespace(ctx context.Context, namespace
 }
 
 const getActivity = `-- name: GetActivity :one
-select id, first_name, last_name, nickname, email, pronouns, namespace, birthday, address, notes
+select id, first_name, last_name, nickname, email, pronouns, namespace, birthday, address, notes, contact_frequency, updated_at, search_vector, version
 from contacts
 where contacts.id = $1
     and contacts.namespace = $2
@@ -231,6 +231,10 @@ func (q *Queries) GetActivity(ctx context.Context, arg GetActivityParams) (Conta
 		&i.Birthday,
 		&i.Address,
 		&i.Notes,
+		&i.ContactFrequency,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Version,
 	)
 	return i, err
 }
@@ -240,6 +244,7 @@ select activities.id as activity_id,
     activities.name,
     activities.date,
     activities.description,
+    activities.version,
     contacts.id as contact_id,
     contacts.first_name,
     contacts.last_name
@@ -261,6 +266,7 @@ type GetActivityAndContactRow struct {
 	Name        string
 	Date        time.Time
 	Description string
+	Version     int32
 	ContactID   int32
 	FirstName   string
 	LastName    string
@@ -274,6 +280,7 @@ func (q *Queries) GetActivityAndContact(ctx context.Context, arg GetActivityAndC
 		&i.Name,
 		&i.Date,
 		&i.Description,
+		&i.Version,
 		&i.ContactID,
 		&i.FirstName,
 		&i.LastName,
@@ -281,35 +288,98 @@ func (q *Queries) GetActivityAndContact(ctx context.Context, arg GetActivityAndC
 	return i, err
 }
 
-const updateActivity = `-- name: UpdateActivity :exec
+const moveActivitiesToContact = `-- name: MoveActivitiesToContact :exec
 update activities
-set name = $4,
-    date = $5,
-    description = $6
+set contact_id = $1
 from contacts
-where contacts.id = $1
-    and contacts.namespace = $2
-    and activities.id = $3
-    and activities.contact_id = contacts.id
+where activities.contact_id = contacts.id
+    and contacts.id = $2
+    and contacts.namespace = $3
 `
 
-type UpdateActivityParams struct {
+type MoveActivitiesToContactParams struct {
+	ContactID int32
+	ID        int32
+	Namespace string
+}
+
+func (q *Queries) MoveActivitiesToContact(ctx context.Context, arg MoveActivitiesToContactParams) error {
+	_, err := q.db.ExecContext(ctx, moveActivitiesToContact, arg.ContactID, arg.ID, arg.Namespace)
+	return err
+}
+
+const restoreActivity = `-- name: RestoreActivity :execrows
+insert into activities (id, name, date, description, contact_id)
+select sqlc.arg(id),
+    sqlc.arg(name),
+    sqlc.arg(date),
+    sqlc.arg(description),
+    contacts.id
+from contacts
+where contacts.id = sqlc.arg(contact_id)
+    and contacts.namespace = sqlc.arg(namespace)
+`
+
+type RestoreActivityParams struct {
 	ID          int32
-	Namespace   string
-	ID_2        int32
 	Name        string
 	Date        time.Time
 	Description string
+	ContactID   int32
+	Namespace   string
 }
 
-func (q *Queries) UpdateActivity(ctx context.Context, arg UpdateActivityParams) error {
-	_, err := q.db.ExecContext(ctx, updateActivity,
+func (q *Queries) RestoreActivity(ctx context.Context, arg RestoreActivityParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restoreActivity,
 		arg.ID,
+		arg.Name,
+		arg.Date,
+		arg.Description,
+		arg.ContactID,
 		arg.Namespace,
-		arg.ID_2,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateActivity = `-- name: UpdateActivity :execrows
+update activities
+set name = $1,
+    date = $2,
+    description = $3,
+    version = activities.version + 1
+from contacts
+where contacts.id = $4
+    and contacts.namespace = $5
+    and activities.id = $6
+    and activities.contact_id = contacts.id
+    and activities.version = $7
+`
+
+type UpdateActivityParams struct {
+	Name        string
+	Date        time.Time
+	Description string
+	ContactID   int32
+	Namespace   string
+	ID          int32
+	Version     int32
+}
+
+func (q *Queries) UpdateActivity(ctx context.Context, arg UpdateActivityParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateActivity,
 		arg.Name,
 		arg.Date,
 		arg.Description,
+		arg.ContactID,
+		arg.Namespace,
+		arg.ID,
+		arg.Version,
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
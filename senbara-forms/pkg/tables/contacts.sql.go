@@ -8,6 +8,7 @@ package tables
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 const createContact = `-- name: CreateContact :one
@@ -73,7 +74,7 @@ func (q *Queries) DeleteContactsForNamespace(ctx context.Context, namespace stri
 }
 
 const getContact = `-- name: GetContact :one
-select id, first_name, last_name, nickname, email, pronouns, namespace, birthday, address, notes
+select id, first_name, last_name, nickname, email, pronouns, namespace, birthday, address, notes, contact_frequency, updated_at, search_vector, version
 from contacts
 where id = $1
     and namespace = $2
@@ -98,12 +99,40 @@ func (q *Queries) GetContact(ctx context.Context, arg GetContactParams) (Contact
 		&i.Birthday,
 		&i.Address,
 		&i.Notes,
+		&i.ContactFrequency,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Version,
 	)
 	return i, err
 }
 
+const getContactIDByName = `-- name: GetContactIDByName :one
+select id
+from contacts
+where namespace = $1
+    and (
+        lower(trim(first_name || ' ' || last_name)) = lower($2::text)
+        or lower(nickname) = lower($2::text)
+    )
+order by id asc
+limit 1
+`
+
+type GetContactIDByNameParams struct {
+	Namespace string
+	Name      string
+}
+
+func (q *Queries) GetContactIDByName(ctx context.Context, arg GetContactIDByNameParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getContactIDByName, arg.Namespace, arg.Name)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
 const getContacts = `-- name: GetContacts :many
-select id, first_name, last_name, nickname, email, pronouns, namespace, birthday, address, notes
+select id, first_name, last_name, nickname, email, pronouns, namespace, birthday, address, notes, contact_frequency, updated_at, search_vector, version
 from contacts
 where namespace = $1
 order by first_name desc
@@ -129,6 +158,282 @@ func (q *Queries) GetContacts(ctx context.Context, namespace string) ([]Contact,
 			&i.Birthday,
 			&i.Address,
 			&i.Notes,
+			&i.ContactFrequency,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getContactsByNameAsc = `-- name: GetContactsByNameAsc :many
+select id, first_name, last_name, nickname, email, pronouns, namespace, birthday, address, notes, contact_frequency, updated_at, search_vector, version
+from contacts
+where namespace = $1
+    and (
+        $2::integer = 0
+        or (first_name, last_name, id) > (
+            $3::text,
+            $4::text,
+            $2::integer
+        )
+    )
+order by first_name asc,
+    last_name asc,
+    id asc
+limit $5
+`
+
+type GetContactsByNameAscParams struct {
+	Namespace      string
+	AfterID        int32
+	AfterFirstName string
+	AfterLastName  string
+	RowLimit       int32
+}
+
+func (q *Queries) GetContactsByNameAsc(ctx context.Context, arg GetContactsByNameAscParams) ([]Contact, error) {
+	rows, err := q.db.QueryContext(ctx, getContactsByNameAsc,
+		arg.Namespace,
+		arg.AfterID,
+		arg.AfterFirstName,
+		arg.AfterLastName,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Contact
+	for rows.Next() {
+		var i Contact
+		if err := rows.Scan(
+			&i.ID,
+			&i.FirstName,
+			&i.LastName,
+			&i.Nickname,
+			&i.Email,
+			&i.Pronouns,
+			&i.Namespace,
+			&i.Birthday,
+			&i.Address,
+			&i.Notes,
+			&i.ContactFrequency,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getContactsByNameDesc = `-- name: GetContactsByNameDesc :many
+select id, first_name, last_name, nickname, email, pronouns, namespace, birthday, address, notes, contact_frequency, updated_at, search_vector, version
+from contacts
+where namespace = $1
+    and (
+        $2::integer = 0
+        or (first_name, last_name, id) < (
+            $3::text,
+            $4::text,
+            $2::integer
+        )
+    )
+order by first_name desc,
+    last_name desc,
+    id desc
+limit $5
+`
+
+type GetContactsByNameDescParams struct {
+	Namespace      string
+	AfterID        int32
+	AfterFirstName string
+	AfterLastName  string
+	RowLimit       int32
+}
+
+func (q *Queries) GetContactsByNameDesc(ctx context.Context, arg GetContactsByNameDescParams) ([]Contact, error) {
+	rows, err := q.db.QueryContext(ctx, getContactsByNameDesc,
+		arg.Namespace,
+		arg.AfterID,
+		arg.AfterFirstName,
+		arg.AfterLastName,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Contact
+	for rows.Next() {
+		var i Contact
+		if err := rows.Scan(
+			&i.ID,
+			&i.FirstName,
+			&i.LastName,
+			&i.Nickname,
+			&i.Email,
+			&i.Pronouns,
+			&i.Namespace,
+			&i.Birthday,
+			&i.Address,
+			&i.Notes,
+			&i.ContactFrequency,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getContactsByUpdatedAtAsc = `-- name: GetContactsByUpdatedAtAsc :many
+select id, first_name, last_name, nickname, email, pronouns, namespace, birthday, address, notes, contact_frequency, updated_at, search_vector, version
+from contacts
+where namespace = $1
+    and (
+        $2::integer = 0
+        or (updated_at, id) > (
+            $3::timestamp,
+            $2::integer
+        )
+    )
+order by updated_at asc,
+    id asc
+limit $4
+`
+
+type GetContactsByUpdatedAtAscParams struct {
+	Namespace      string
+	AfterID        int32
+	AfterUpdatedAt time.Time
+	RowLimit       int32
+}
+
+func (q *Queries) GetContactsByUpdatedAtAsc(ctx context.Context, arg GetContactsByUpdatedAtAscParams) ([]Contact, error) {
+	rows, err := q.db.QueryContext(ctx, getContactsByUpdatedAtAsc,
+		arg.Namespace,
+		arg.AfterID,
+		arg.AfterUpdatedAt,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Contact
+	for rows.Next() {
+		var i Contact
+		if err := rows.Scan(
+			&i.ID,
+			&i.FirstName,
+			&i.LastName,
+			&i.Nickname,
+			&i.Email,
+			&i.Pronouns,
+			&i.Namespace,
+			&i.Birthday,
+			&i.Address,
+			&i.Notes,
+			&i.ContactFrequency,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getContactsByUpdatedAtDesc = `-- name: GetContactsByUpdatedAtDesc :many
+select id, first_name, last_name, nickname, email, pronouns, namespace, birthday, address, notes, contact_frequency, updated_at, search_vector, version
+from contacts
+where namespace = $1
+    and (
+        $2::integer = 0
+        or (updated_at, id) < (
+            $3::timestamp,
+            $2::integer
+        )
+    )
+order by updated_at desc,
+    id desc
+limit $4
+`
+
+type GetContactsByUpdatedAtDescParams struct {
+	Namespace      string
+	AfterID        int32
+	AfterUpdatedAt time.Time
+	RowLimit       int32
+}
+
+func (q *Queries) GetContactsByUpdatedAtDesc(ctx context.Context, arg GetContactsByUpdatedAtDescParams) ([]Contact, error) {
+	rows, err := q.db.QueryContext(ctx, getContactsByUpdatedAtDesc,
+		arg.Namespace,
+		arg.AfterID,
+		arg.AfterUpdatedAt,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Contact
+	for rows.Next() {
+		var i Contact
+		if err := rows.Scan(
+			&i.ID,
+			&i.FirstName,
+			&i.LastName,
+			&i.Nickname,
+			&i.Email,
+			&i.Pronouns,
+			&i.Namespace,
+			&i.Birthday,
+			&i.Address,
+			&i.Notes,
+			&i.ContactFrequency,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -145,24 +450,28 @@ func (q *Queries) GetContacts(ctx context.Context, namespace string) ([]Contact,
 
 const getContactsExportForNamespace = `-- name: GetContactsExportForNamespace :many
 select 'contacts' as table_name,
-    id, first_name, last_name, nickname, email, pronouns, namespace, birthday, address, notes
+    id, first_name, last_name, nickname, email, pronouns, namespace, birthday, address, notes, contact_frequency, updated_at, search_vector, version
 from contacts
 where namespace = $1
 order by first_name desc
 `
 
 type GetContactsExportForNamespaceRow struct {
-	TableName string
-	ID        int32
-	FirstName string
-	LastName  string
-	Nickname  string
-	Email     string
-	Pronouns  string
-	Namespace string
-	Birthday  sql.NullTime
-	Address   string
-	Notes     string
+	TableName        string
+	ID               int32
+	FirstName        string
+	LastName         string
+	Nickname         string
+	Email            string
+	Pronouns         string
+	Namespace        string
+	Birthday         sql.NullTime
+	Address          string
+	Notes            string
+	ContactFrequency string
+	UpdatedAt        time.Time
+	SearchVector     interface{}
+	Version          int32
 }
 
 func (q *Queries) GetContactsExportForNamespace(ctx context.Context, namespace string) ([]GetContactsExportForNamespaceRow, error) {
@@ -186,6 +495,10 @@ func (q *Queries) GetContactsExportForNamespace(ctx context.Context, namespace s
 			&i.Birthday,
 			&i.Address,
 			&i.Notes,
+			&i.ContactFrequency,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -200,45 +513,181 @@ func (q *Queries) GetContactsExportForNamespace(ctx context.Context, namespace s
 	return items, nil
 }
 
-const updateContact = `-- name: UpdateContact :exec
-update contacts
-set first_name = $3,
-    last_name = $4,
-    nickname = $5,
-    email = $6,
-    pronouns = $7,
-    birthday = $8,
-    address = $9,
-    notes = $10
-where id = $1
+const getContactStatus = `-- name: GetContactStatus :one
+select contact_id, namespace, last_contacted, overdue
+from contact_statuses
+where contact_id = $1
     and namespace = $2
 `
 
-type UpdateContactParams struct {
-	ID        int32
+type GetContactStatusParams struct {
+	ContactID int32
 	Namespace string
-	FirstName string
-	LastName  string
-	Nickname  string
-	Email     string
-	Pronouns  string
-	Birthday  sql.NullTime
-	Address   string
-	Notes     string
 }
 
-func (q *Queries) UpdateContact(ctx context.Context, arg UpdateContactParams) error {
-	_, err := q.db.ExecContext(ctx, updateContact,
+func (q *Queries) GetContactStatus(ctx context.Context, arg GetContactStatusParams) (ContactStatus, error) {
+	row := q.db.QueryRowContext(ctx, getContactStatus, arg.ContactID, arg.Namespace)
+	var i ContactStatus
+	err := row.Scan(
+		&i.ContactID,
+		&i.Namespace,
+		&i.LastContacted,
+		&i.Overdue,
+	)
+	return i, err
+}
+
+const getOverdueContacts = `-- name: GetOverdueContacts :many
+select contacts.id,
+    contacts.first_name,
+    contacts.last_name,
+    contacts.contact_frequency,
+    contact_statuses.last_contacted
+from contacts
+    join contact_statuses on contact_statuses.contact_id = contacts.id
+where contacts.namespace = $1
+    and contact_statuses.overdue
+order by contact_statuses.last_contacted asc nulls first
+`
+
+type GetOverdueContactsRow struct {
+	ID               int32
+	FirstName        string
+	LastName         string
+	ContactFrequency string
+	LastContacted    sql.NullTime
+}
+
+func (q *Queries) GetOverdueContacts(ctx context.Context, namespace string) ([]GetOverdueContactsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getOverdueContacts, namespace)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOverdueContactsRow
+	for rows.Next() {
+		var i GetOverdueContactsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FirstName,
+			&i.LastName,
+			&i.ContactFrequency,
+			&i.LastContacted,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreContact = `-- name: RestoreContact :exec
+insert into contacts (
+        id,
+        first_name,
+        last_name,
+        nickname,
+        email,
+        pronouns,
+        namespace,
+        birthday,
+        address,
+        notes,
+        contact_frequency
+    )
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+`
+
+type RestoreContactParams struct {
+	ID               int32
+	FirstName        string
+	LastName         string
+	Nickname         string
+	Email            string
+	Pronouns         string
+	Namespace        string
+	Birthday         sql.NullTime
+	Address          string
+	Notes            string
+	ContactFrequency string
+}
+
+func (q *Queries) RestoreContact(ctx context.Context, arg RestoreContactParams) error {
+	_, err := q.db.ExecContext(ctx, restoreContact,
 		arg.ID,
-		arg.Namespace,
 		arg.FirstName,
 		arg.LastName,
 		arg.Nickname,
 		arg.Email,
 		arg.Pronouns,
+		arg.Namespace,
 		arg.Birthday,
 		arg.Address,
 		arg.Notes,
+		arg.ContactFrequency,
 	)
 	return err
 }
+
+const updateContact = `-- name: UpdateContact :execrows
+update contacts
+set first_name = $1,
+    last_name = $2,
+    nickname = $3,
+    email = $4,
+    pronouns = $5,
+    birthday = $6,
+    address = $7,
+    notes = $8,
+    contact_frequency = $9,
+    updated_at = now(),
+    version = version + 1
+where id = $10
+    and namespace = $11
+    and (
+        $12::integer is null
+        or version = $12
+    )
+`
+
+type UpdateContactParams struct {
+	FirstName        string
+	LastName         string
+	Nickname         string
+	Email            string
+	Pronouns         string
+	Birthday         sql.NullTime
+	Address          string
+	Notes            string
+	ContactFrequency string
+	ID               int32
+	Namespace        string
+	Version          sql.NullInt32
+}
+
+func (q *Queries) UpdateContact(ctx context.Context, arg UpdateContactParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateContact,
+		arg.FirstName,
+		arg.LastName,
+		arg.Nickname,
+		arg.Email,
+		arg.Pronouns,
+		arg.Birthday,
+		arg.Address,
+		arg.Notes,
+		arg.ContactFrequency,
+		arg.ID,
+		arg.Namespace,
+		arg.Version,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -88,6 +88,7 @@ select debts.id as debt_id,
     debts.amount,
     debts.currency,
     debts.description,
+    debts.version,
     contacts.id as contact_id,
     contacts.first_name,
     contacts.last_name
@@ -109,6 +110,7 @@ type GetDebtAndContactRow struct {
 	Amount      float64
 	Currency    string
 	Description string
+	Version     int32
 	ContactID   int32
 	FirstName   string
 	LastName    string
@@ -122,6 +124,7 @@ func (q *Queries) GetDebtAndContact(ctx context.Context, arg GetDebtAndContactPa
 		&i.Amount,
 		&i.Currency,
 		&i.Description,
+		&i.Version,
 		&i.ContactID,
 		&i.FirstName,
 		&i.LastName,
@@ -231,6 +234,62 @@ func (q *Queries) GetDebtsExportForNamespace(ctx context.Context, namespace stri
 	return items, nil
 }
 
+const moveDebtsToContact = `-- name: MoveDebtsToContact :exec
+update debts
+set contact_id = $1
+from contacts
+where debts.contact_id = contacts.id
+    and contacts.id = $2
+    and contacts.namespace = $3
+`
+
+type MoveDebtsToContactParams struct {
+	ContactID int32
+	ID        int32
+	Namespace string
+}
+
+func (q *Queries) MoveDebtsToContact(ctx context.Context, arg MoveDebtsToContactParams) error {
+	_, err := q.db.ExecContext(ctx, moveDebtsToContact, arg.ContactID, arg.ID, arg.Namespace)
+	return err
+}
+
+const restoreDebt = `-- name: RestoreDebt :execrows
+insert into debts (id, amount, currency, description, contact_id)
+select sqlc.arg(id),
+    sqlc.arg(amount),
+    sqlc.arg(currency),
+    sqlc.arg(description),
+    contacts.id
+from contacts
+where contacts.id = sqlc.arg(contact_id)
+    and contacts.namespace = sqlc.arg(namespace)
+`
+
+type RestoreDebtParams struct {
+	ID          int32
+	Amount      float64
+	Currency    string
+	Description string
+	ContactID   int32
+	Namespace   string
+}
+
+func (q *Queries) RestoreDebt(ctx context.Context, arg RestoreDebtParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restoreDebt,
+		arg.ID,
+		arg.Amount,
+		arg.Currency,
+		arg.Description,
+		arg.ContactID,
+		arg.Namespace,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const settleDebt = `-- name: SettleDebt :exec
 delete from debts using contacts
 where debts.id = $3
@@ -250,35 +309,42 @@ func (q *Queries) SettleDebt(ctx context.Context, arg SettleDebtParams) error {
 	return err
 }
 
-const updateDebt = `-- name: UpdateDebt :exec
+const updateDebt = `-- name: UpdateDebt :execrows
 update debts
-set amount = $4,
-    currency = $5,
-    description = $6
+set amount = $1,
+    currency = $2,
+    description = $3,
+    version = debts.version + 1
 from contacts
-where contacts.id = $1
-    and contacts.namespace = $2
-    and debts.id = $3
+where contacts.id = $4
+    and contacts.namespace = $5
+    and debts.id = $6
     and debts.contact_id = contacts.id
+    and debts.version = $7
 `
 
 type UpdateDebtParams struct {
-	ID          int32
-	Namespace   string
-	ID_2        int32
 	Amount      float64
 	Currency    string
 	Description string
+	ContactID   int32
+	Namespace   string
+	ID          int32
+	Version     int32
 }
 
-func (q *Queries) UpdateDebt(ctx context.Context, arg UpdateDebtParams) error {
-	_, err := q.db.ExecContext(ctx, updateDebt,
-		arg.ID,
-		arg.Namespace,
-		arg.ID_2,
+func (q *Queries) UpdateDebt(ctx context.Context, arg UpdateDebtParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateDebt,
 		arg.Amount,
 		arg.Currency,
 		arg.Description,
+		arg.ContactID,
+		arg.Namespace,
+		arg.ID,
+		arg.Version,
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
@@ -7,17 +7,21 @@ package tables
 
 import (
 	"context"
+	"database/sql"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 const createJournalEntry = `-- name: CreateJournalEntry :one
-insert into journal_entries (title, body, rating, namespace)
-values ($1, $2, $3, $4)
+insert into journal_entries (title, date, body, rating, namespace)
+values ($1, $2, $3, $4, $5)
 returning id
 `
 
 type CreateJournalEntryParams struct {
 	Title     string
+	Date      time.Time
 	Body      string
 	Rating    int32
 	Namespace string
@@ -26,6 +30,7 @@ type CreateJournalEntryParams struct {
 func (q *Queries) CreateJournalEntry(ctx context.Context, arg CreateJournalEntryParams) (int32, error) {
 	row := q.db.QueryRowContext(ctx, createJournalEntry,
 		arg.Title,
+		arg.Date,
 		arg.Body,
 		arg.Rating,
 		arg.Namespace,
@@ -62,7 +67,7 @@ func (q *Queries) DeleteJournalEntry(ctx context.Context, arg DeleteJournalEntry
 }
 
 const getJournalEntries = `-- name: GetJournalEntries :many
-select id, title, date, body, rating, namespace
+select id, title, date, body, rating, namespace, search_vector, version
 from journal_entries
 where namespace = $1
 order by date desc
@@ -84,6 +89,292 @@ func (q *Queries) GetJournalEntries(ctx context.Context, namespace string) ([]Jo
 			&i.Body,
 			&i.Rating,
 			&i.Namespace,
+			&i.SearchVector,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getJournalEntriesByDateAsc = `-- name: GetJournalEntriesByDateAsc :many
+select id, title, date, body, rating, namespace, search_vector, version
+from journal_entries
+where namespace = $1
+    and (
+        cardinality($2::text []) = 0
+        or (
+            select count(*)
+            from journal_entry_tags
+            where journal_entry_tags.journal_entry_id = journal_entries.id
+                and journal_entry_tags.name = any($2::text [])
+        ) = cardinality($2::text [])
+    )
+    and (
+        $3::integer = 0
+        or (date, id) > (
+            $4::timestamp,
+            $3::integer
+        )
+    )
+order by date asc,
+    id asc
+limit $5
+`
+
+type GetJournalEntriesByDateAscParams struct {
+	Namespace string
+	Tags      []string
+	AfterID   int32
+	AfterDate time.Time
+	RowLimit  int32
+}
+
+func (q *Queries) GetJournalEntriesByDateAsc(ctx context.Context, arg GetJournalEntriesByDateAscParams) ([]JournalEntry, error) {
+	rows, err := q.db.QueryContext(ctx, getJournalEntriesByDateAsc,
+		arg.Namespace,
+		pq.Array(arg.Tags),
+		arg.AfterID,
+		arg.AfterDate,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []JournalEntry
+	for rows.Next() {
+		var i JournalEntry
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Date,
+			&i.Body,
+			&i.Rating,
+			&i.Namespace,
+			&i.SearchVector,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getJournalEntriesByDateDesc = `-- name: GetJournalEntriesByDateDesc :many
+select id, title, date, body, rating, namespace, search_vector, version
+from journal_entries
+where namespace = $1
+    and (
+        cardinality($2::text []) = 0
+        or (
+            select count(*)
+            from journal_entry_tags
+            where journal_entry_tags.journal_entry_id = journal_entries.id
+                and journal_entry_tags.name = any($2::text [])
+        ) = cardinality($2::text [])
+    )
+    and (
+        $3::integer = 0
+        or (date, id) < (
+            $4::timestamp,
+            $3::integer
+        )
+    )
+order by date desc,
+    id desc
+limit $5
+`
+
+type GetJournalEntriesByDateDescParams struct {
+	Namespace string
+	Tags      []string
+	AfterID   int32
+	AfterDate time.Time
+	RowLimit  int32
+}
+
+func (q *Queries) GetJournalEntriesByDateDesc(ctx context.Context, arg GetJournalEntriesByDateDescParams) ([]JournalEntry, error) {
+	rows, err := q.db.QueryContext(ctx, getJournalEntriesByDateDesc,
+		arg.Namespace,
+		pq.Array(arg.Tags),
+		arg.AfterID,
+		arg.AfterDate,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []JournalEntry
+	for rows.Next() {
+		var i JournalEntry
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Date,
+			&i.Body,
+			&i.Rating,
+			&i.Namespace,
+			&i.SearchVector,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getJournalEntriesByRatingAsc = `-- name: GetJournalEntriesByRatingAsc :many
+select id, title, date, body, rating, namespace, search_vector, version
+from journal_entries
+where namespace = $1
+    and (
+        cardinality($2::text []) = 0
+        or (
+            select count(*)
+            from journal_entry_tags
+            where journal_entry_tags.journal_entry_id = journal_entries.id
+                and journal_entry_tags.name = any($2::text [])
+        ) = cardinality($2::text [])
+    )
+    and (
+        $3::integer = 0
+        or (rating, id) > (
+            $4::integer,
+            $3::integer
+        )
+    )
+order by rating asc,
+    id asc
+limit $5
+`
+
+type GetJournalEntriesByRatingAscParams struct {
+	Namespace   string
+	Tags        []string
+	AfterID     int32
+	AfterRating int32
+	RowLimit    int32
+}
+
+func (q *Queries) GetJournalEntriesByRatingAsc(ctx context.Context, arg GetJournalEntriesByRatingAscParams) ([]JournalEntry, error) {
+	rows, err := q.db.QueryContext(ctx, getJournalEntriesByRatingAsc,
+		arg.Namespace,
+		pq.Array(arg.Tags),
+		arg.AfterID,
+		arg.AfterRating,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []JournalEntry
+	for rows.Next() {
+		var i JournalEntry
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Date,
+			&i.Body,
+			&i.Rating,
+			&i.Namespace,
+			&i.SearchVector,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getJournalEntriesByRatingDesc = `-- name: GetJournalEntriesByRatingDesc :many
+select id, title, date, body, rating, namespace, search_vector, version
+from journal_entries
+where namespace = $1
+    and (
+        cardinality($2::text []) = 0
+        or (
+            select count(*)
+            from journal_entry_tags
+            where journal_entry_tags.journal_entry_id = journal_entries.id
+                and journal_entry_tags.name = any($2::text [])
+        ) = cardinality($2::text [])
+    )
+    and (
+        $3::integer = 0
+        or (rating, id) < (
+            $4::integer,
+            $3::integer
+        )
+    )
+order by rating desc,
+    id desc
+limit $5
+`
+
+type GetJournalEntriesByRatingDescParams struct {
+	Namespace   string
+	Tags        []string
+	AfterID     int32
+	AfterRating int32
+	RowLimit    int32
+}
+
+func (q *Queries) GetJournalEntriesByRatingDesc(ctx context.Context, arg GetJournalEntriesByRatingDescParams) ([]JournalEntry, error) {
+	rows, err := q.db.QueryContext(ctx, getJournalEntriesByRatingDesc,
+		arg.Namespace,
+		pq.Array(arg.Tags),
+		arg.AfterID,
+		arg.AfterRating,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []JournalEntry
+	for rows.Next() {
+		var i JournalEntry
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Date,
+			&i.Body,
+			&i.Rating,
+			&i.Namespace,
+			&i.SearchVector,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -100,20 +391,22 @@ func (q *Queries) GetJournalEntries(ctx context.Context, namespace string) ([]Jo
 
 const getJournalEntriesExportForNamespace = `-- name: GetJournalEntriesExportForNamespace :many
 select 'journal_entries' as table_name,
-    id, title, date, body, rating, namespace
+    id, title, date, body, rating, namespace, search_vector, version
 from journal_entries
 where namespace = $1
 order by date desc
 `
 
 type GetJournalEntriesExportForNamespaceRow struct {
-	TableName string
-	ID        int32
-	Title     string
-	Date      time.Time
-	Body      string
-	Rating    int32
-	Namespace string
+	TableName    string
+	ID           int32
+	Title        string
+	Date         time.Time
+	Body         string
+	Rating       int32
+	Namespace    string
+	SearchVector interface{}
+	Version      int32
 }
 
 func (q *Queries) GetJournalEntriesExportForNamespace(ctx context.Context, namespace string) ([]GetJournalEntriesExportForNamespaceRow, error) {
@@ -133,6 +426,8 @@ func (q *Queries) GetJournalEntriesExportForNamespace(ctx context.Context, names
 			&i.Body,
 			&i.Rating,
 			&i.Namespace,
+			&i.SearchVector,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -148,7 +443,7 @@ func (q *Queries) GetJournalEntriesExportForNamespace(ctx context.Context, names
 }
 
 const getJournalEntry = `-- name: GetJournalEntry :one
-select id, title, date, body, rating, namespace
+select id, title, date, body, rating, namespace, search_vector, version
 from journal_entries
 where id = $1
     and namespace = $2
@@ -169,34 +464,156 @@ func (q *Queries) GetJournalEntry(ctx context.Context, arg GetJournalEntryParams
 		&i.Body,
 		&i.Rating,
 		&i.Namespace,
+		&i.SearchVector,
+		&i.Version,
 	)
 	return i, err
 }
 
-const updateJournalEntry = `-- name: UpdateJournalEntry :exec
+const getJournalEntryRatings = `-- name: GetJournalEntryRatings :many
+select date,
+    rating
+from journal_entries
+where namespace = $1
+order by date
+`
+
+type GetJournalEntryRatingsRow struct {
+	Date   time.Time
+	Rating int32
+}
+
+func (q *Queries) GetJournalEntryRatings(ctx context.Context, namespace string) ([]GetJournalEntryRatingsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getJournalEntryRatings, namespace)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetJournalEntryRatingsRow
+	for rows.Next() {
+		var i GetJournalEntryRatingsRow
+		if err := rows.Scan(&i.Date, &i.Rating); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreJournalEntry = `-- name: RestoreJournalEntry :exec
+insert into journal_entries (id, title, date, body, rating, namespace)
+values ($1, $2, $3, $4, $5, $6)
+`
+
+type RestoreJournalEntryParams struct {
+	ID        int32
+	Title     string
+	Date      time.Time
+	Body      string
+	Rating    int32
+	Namespace string
+}
+
+func (q *Queries) RestoreJournalEntry(ctx context.Context, arg RestoreJournalEntryParams) error {
+	_, err := q.db.ExecContext(ctx, restoreJournalEntry,
+		arg.ID,
+		arg.Title,
+		arg.Date,
+		arg.Body,
+		arg.Rating,
+		arg.Namespace,
+	)
+	return err
+}
+
+const updateJournalEntry = `-- name: UpdateJournalEntry :execrows
+update journal_entries
+set title = $1,
+    body = $2,
+    rating = $3,
+    date = $4,
+    version = version + 1
+where id = $5
+    and namespace = $6
+    and (
+        $7::integer is null
+        or version = $7
+    )
+`
+
+type UpdateJournalEntryParams struct {
+	Title     string
+	Body      string
+	Rating    int32
+	Date      time.Time
+	ID        int32
+	Namespace string
+	Version   sql.NullInt32
+}
+
+func (q *Queries) UpdateJournalEntry(ctx context.Context, arg UpdateJournalEntryParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateJournalEntry,
+		arg.Title,
+		arg.Body,
+		arg.Rating,
+		arg.Date,
+		arg.ID,
+		arg.Namespace,
+		arg.Version,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateJournalEntryBody = `-- name: UpdateJournalEntryBody :exec
+update journal_entries
+set body = $3,
+    version = version + 1
+where id = $1
+    and namespace = $2
+`
+
+type UpdateJournalEntryBodyParams struct {
+	ID        int32
+	Namespace string
+	Body      string
+}
+
+func (q *Queries) UpdateJournalEntryBody(ctx context.Context, arg UpdateJournalEntryBodyParams) error {
+	_, err := q.db.ExecContext(ctx, updateJournalEntryBody, arg.ID, arg.Namespace, arg.Body)
+	return err
+}
+
+const updateJournalEntryTitleAndBody = `-- name: UpdateJournalEntryTitleAndBody :exec
 update journal_entries
 set title = $3,
     body = $4,
-    rating = $5
+    version = version + 1
 where id = $1
     and namespace = $2
 `
 
-type UpdateJournalEntryParams struct {
+type UpdateJournalEntryTitleAndBodyParams struct {
 	ID        int32
 	Namespace string
 	Title     string
 	Body      string
-	Rating    int32
 }
 
-func (q *Queries) UpdateJournalEntry(ctx context.Context, arg UpdateJournalEntryParams) error {
-	_, err := q.db.ExecContext(ctx, updateJournalEntry,
+func (q *Queries) UpdateJournalEntryTitleAndBody(ctx context.Context, arg UpdateJournalEntryTitleAndBodyParams) error {
+	_, err := q.db.ExecContext(ctx, updateJournalEntryTitleAndBody,
 		arg.ID,
 		arg.Namespace,
 		arg.Title,
 		arg.Body,
-		arg.Rating,
 	)
 	return err
 }
@@ -6,43 +6,153 @@ package tables
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 )
 
 type Activity struct {
+	ID           int32
+	Name         string
+	Date         time.Time
+	ContactID    int32
+	Description  string
+	SearchVector interface{}
+	Version      int32
+}
+
+type Attachment struct {
+	ID             int32
+	JournalEntryID int32
+	Name           string
+	ContentType    string
+	Size           int32
+	BlobKey        string
+	Namespace      string
+	CreatedAt      time.Time
+}
+
+type BackupRun struct {
+	Namespace       string
+	RanAt           time.Time
+	FileName        string
+	Size            int64
+	Error           string
+	LastSucceededAt sql.NullTime
+	JournalSkipped  bool
+}
+
+type Contact struct {
+	ID               int32
+	FirstName        string
+	LastName         string
+	Nickname         string
+	Email            string
+	Pronouns         string
+	Namespace        string
+	Birthday         sql.NullTime
+	Address          string
+	Notes            string
+	ContactFrequency string
+	UpdatedAt        time.Time
+	SearchVector     interface{}
+	Version          int32
+}
+
+type ContactPhoto struct {
 	ID          int32
-	Name        string
-	Date        time.Time
 	ContactID   int32
-	Description string
+	Size        int32
+	ContentType string
+	Data        []byte
+	Namespace   string
+	CreatedAt   time.Time
 }
 
-type Contact struct {
+type ContactStatus struct {
+	ContactID     int32
+	Namespace     string
+	LastContacted sql.NullTime
+	Overdue       bool
+}
+
+type CustomField struct {
 	ID        int32
-	FirstName string
-	LastName  string
-	Nickname  string
-	Email     string
-	Pronouns  string
+	Name      string
+	Kind      string
+	Options   []string
 	Namespace string
-	Birthday  sql.NullTime
-	Address   string
-	Notes     string
+}
+
+type CustomFieldValue struct {
+	ID            int32
+	ContactID     int32
+	CustomFieldID int32
+	Value         string
 }
 
 type Debt struct {
-	ID          int32
-	Amount      float64
-	Currency    string
-	ContactID   int32
-	Description string
+	ID           int32
+	Amount       float64
+	Currency     string
+	ContactID    int32
+	Description  string
+	SearchVector interface{}
+	Version      int32
 }
 
 type JournalEntry struct {
-	ID        int32
-	Title     string
-	Date      time.Time
-	Body      string
-	Rating    int32
-	Namespace string
+	ID           int32
+	Title        string
+	Date         time.Time
+	Body         string
+	Rating       int32
+	Namespace    string
+	SearchVector interface{}
+	Version      int32
+}
+
+type JournalEntryMention struct {
+	JournalEntryID int32
+	ContactID      int32
+}
+
+type JournalEntryTag struct {
+	JournalEntryID int32
+	Name           string
+}
+
+type Relationship struct {
+	ID               int32
+	ContactID        int32
+	RelatedContactID int32
+	Kind             string
+}
+
+type Revision struct {
+	ID         int32
+	EntityName string
+	EntityID   int32
+	Data       json.RawMessage
+	Namespace  string
+	CreatedAt  time.Time
+}
+
+type Setting struct {
+	Namespace              string
+	TimeZone               string
+	SearchLanguage         string
+	PreviewFormat          string
+	JournalEncrypted       bool
+	JournalEncryptionSalt  []byte
+	JournalEncryptionCheck string
+}
+
+type TrashItem struct {
+	ID         int32
+	EntityName string
+	Title      string
+	Data       json.RawMessage
+	Namespace  string
+	DeletedAt  time.Time
+	EntityID   int32
 }
@@ -0,0 +1,372 @@
+package userdata
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/persisters"
+)
+
+const (
+	ManifestVersion = 1
+
+	manifestPath   = "manifest.json"
+	dataPath       = "data.jsonl"
+	blobsDirectory = "blobs/"
+
+	maxManifestSize = 16 * 1024 * 1024
+)
+
+var (
+	ErrInvalidArchive   = errors.New("invalid backup archive")
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+)
+
+// Manifest lists the files of a backup archive with their SHA-256 checksums. The manifest itself isn't listed.
+type Manifest struct {
+	Version   int            `json:"version"`
+	CreatedAt time.Time      `json:"createdAt"`
+	Files     []ManifestFile `json:"files"`
+}
+
+type ManifestFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ExportArchive writes the user data of a namespace to `w` as a backup archive. The archive is a zip file
+// with the rows in `data.jsonl`, the binary data of contact photos and attachments in `blobs/` and
+// a `manifest.json` with the checksums of all of them.
+func ExportArchive(ctx context.Context, persister *persisters.Persister, key []byte, namespace string, w io.Writer) error {
+	return exportArchive(ctx, persister, key, namespace, w, nil)
+}
+
+// ExportArchiveWithoutLockedJournal works like ExportArchive, but if the journal is encrypted and can't be
+// decrypted with `key`, its entries and their attachments are left out instead of failing. It returns
+// whether they were left out.
+func ExportArchiveWithoutLockedJournal(ctx context.Context, persister *persisters.Persister, key []byte, namespace string, w io.Writer) (bool, error) {
+	journalSkipped := false
+	if err := exportArchive(ctx, persister, key, namespace, w, func() error {
+		journalSkipped = true
+
+		return nil
+	}); err != nil {
+		return false, err
+	}
+
+	return journalSkipped, nil
+}
+
+func exportArchive(
+	ctx context.Context,
+	persister *persisters.Persister,
+
+	key []byte,
+	namespace string,
+
+	w io.Writer,
+	onJournalSkipped func() error,
+) error {
+	a, err := newArchiveWriter(w)
+	if err != nil {
+		return errors.Join(ErrCouldNotWriteUserData, err)
+	}
+	defer a.remove()
+
+	encoder := json.NewEncoder(a.rows)
+
+	if err := export(ctx, persister, key, namespace, func(row any) error {
+		if err := encoder.Encode(row); err != nil {
+			return errors.Join(ErrCouldNotWriteUserData, err)
+		}
+
+		return nil
+	}, a.writeBlob, onJournalSkipped); err != nil {
+		return err
+	}
+
+	if err := a.close(); err != nil {
+		return errors.Join(ErrCouldNotWriteUserData, err)
+	}
+
+	return nil
+}
+
+// ImportArchive creates the user data in a backup archive in a namespace. The checksums of all files
+// in the archive are verified before the transaction is committed.
+func ImportArchive(ctx context.Context, persister *persisters.Persister, key []byte, namespace string, r io.ReaderAt, size int64) error {
+	a, err := newArchiveReader(r, size)
+	if err != nil {
+		return err
+	}
+
+	data, err := a.open(dataPath)
+	if err != nil {
+		return err
+	}
+	defer data.Close()
+
+	return importRows(ctx, persister, key, namespace, json.NewDecoder(data), a.readBlob, a.verify)
+}
+
+type archiveWriter struct {
+	archive *zip.Writer
+	files   []ManifestFile
+
+	// Rows are spooled to a temporary file since a zip file can only be written one file at a time, and blobs are written as they come
+	data *os.File
+	rows *bufio.Writer
+}
+
+func newArchiveWriter(w io.Writer) (*archiveWriter, error) {
+	data, err := os.CreateTemp("", "senbara-forms-data-*.jsonl")
+	if err != nil {
+		return nil, err
+	}
+
+	return &archiveWriter{
+		archive: zip.NewWriter(w),
+
+		data: data,
+		rows: bufio.NewWriter(data),
+	}, nil
+}
+
+// writeFile copies `r` into a new file in the archive and adds its checksum to the manifest
+func (a *archiveWriter) writeFile(path string, r io.Reader) error {
+	f, err := a.archive.CreateHeader(&zip.FileHeader{
+		Name:     path,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.New()
+
+	size, err := io.Copy(io.MultiWriter(f, hash), r)
+	if err != nil {
+		return err
+	}
+
+	a.files = append(a.files, ManifestFile{
+		Path:   path,
+		Size:   size,
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+	})
+
+	return nil
+}
+
+func (a *archiveWriter) writeBlob(name string, data io.Reader) (string, error) {
+	path := blobsDirectory + name
+
+	return path, a.writeFile(path, data)
+}
+
+func (a *archiveWriter) close() error {
+	if err := a.rows.Flush(); err != nil {
+		return err
+	}
+
+	if _, err := a.data.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	if err := a.writeFile(dataPath, a.data); err != nil {
+		return err
+	}
+
+	manifest, err := json.MarshalIndent(Manifest{
+		Version:   ManifestVersion,
+		CreatedAt: time.Now().UTC(),
+		Files:     a.files,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := a.archive.CreateHeader(&zip.FileHeader{
+		Name:     manifestPath,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(manifest); err != nil {
+		return err
+	}
+
+	return a.archive.Close()
+}
+
+// remove deletes the temporary file that the rows were spooled to
+func (a *archiveWriter) remove() error {
+	_ = a.data.Close()
+
+	return os.Remove(a.data.Name())
+}
+
+type archiveReader struct {
+	files    map[string]*zip.File
+	manifest map[string]ManifestFile
+	verified map[string]struct{}
+}
+
+func newArchiveReader(r io.ReaderAt, size int64) (*archiveReader, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidArchive, err)
+	}
+
+	a := &archiveReader{
+		files:    map[string]*zip.File{},
+		manifest: map[string]ManifestFile{},
+		verified: map[string]struct{}{},
+	}
+
+	for _, f := range archive.File {
+		a.files[f.Name] = f
+	}
+
+	f, ok := a.files[manifestPath]
+	if !ok {
+		return nil, fmt.Errorf("%w: missing %v", ErrInvalidArchive, manifestPath)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, errors.Join(ErrInvalidArchive, err)
+	}
+	defer rc.Close()
+
+	var manifest Manifest
+	if err := json.NewDecoder(io.LimitReader(rc, maxManifestSize)).Decode(&manifest); err != nil {
+		return nil, errors.Join(ErrInvalidArchive, err)
+	}
+
+	if manifest.Version != ManifestVersion {
+		return nil, fmt.Errorf("%w: unsupported version %v", ErrInvalidArchive, manifest.Version)
+	}
+
+	for _, file := range manifest.Files {
+		if _, ok := a.files[file.Path]; !ok {
+			return nil, fmt.Errorf("%w: missing %v", ErrInvalidArchive, file.Path)
+		}
+
+		a.manifest[file.Path] = file
+	}
+
+	if _, ok := a.manifest[dataPath]; !ok {
+		return nil, fmt.Errorf("%w: missing %v", ErrInvalidArchive, dataPath)
+	}
+
+	return a, nil
+}
+
+// open returns a reader for a file in the manifest that fails if the file doesn't match its checksum
+func (a *archiveReader) open(path string) (io.ReadCloser, error) {
+	file, ok := a.manifest[path]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v is not in the manifest", ErrInvalidArchive, path)
+	}
+
+	rc, err := a.files[path].Open()
+	if err != nil {
+		return nil, errors.Join(ErrInvalidArchive, err)
+	}
+
+	return &verifyingReader{
+		ReadCloser: rc,
+
+		hash: sha256.New(),
+		file: file,
+
+		onVerified: func() {
+			a.verified[path] = struct{}{}
+		},
+	}, nil
+}
+
+func (a *archiveReader) readBlob(path string) ([]byte, error) {
+	if !strings.HasPrefix(path, blobsDirectory) {
+		return nil, fmt.Errorf("%w: %v is not a blob", ErrInvalidArchive, path)
+	}
+
+	rc, err := a.open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return io.ReadAll(rc)
+}
+
+// verify checks the files in the manifest that haven't been read completely yet
+func (a *archiveReader) verify() error {
+	for path := range a.manifest {
+		if _, ok := a.verified[path]; ok {
+			continue
+		}
+
+		rc, err := a.open(path)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(io.Discard, rc)
+		_ = rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type verifyingReader struct {
+	io.ReadCloser
+
+	hash hash.Hash
+	size int64
+	file ManifestFile
+
+	onVerified func()
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+
+	r.hash.Write(p[:n])
+	r.size += int64(n)
+
+	// This also prevents reading more than the expected size from compressed files
+	if r.size > r.file.Size {
+		return n, fmt.Errorf("%w: %v is larger than expected", ErrChecksumMismatch, r.file.Path)
+	}
+
+	if errors.Is(err, io.EOF) {
+		if r.size != r.file.Size || hex.EncodeToString(r.hash.Sum(nil)) != r.file.SHA256 {
+			return n, fmt.Errorf("%w: %v", ErrChecksumMismatch, r.file.Path)
+		}
+
+		r.onVerified()
+	}
+
+	return n, err
+}
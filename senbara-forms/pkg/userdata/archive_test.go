@@ -0,0 +1,83 @@
+package userdata
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestArchiveWriterRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+
+	a, err := newArchiveWriter(&buf)
+	if err != nil {
+		t.Fatalf("could not create archive writer: %v", err)
+	}
+
+	spooledPath := a.data.Name()
+
+	// The blob is larger than the copy buffer, so that it is written in multiple chunks
+	blob := strings.Repeat("0123456789", 10*1024)
+	path, err := a.writeBlob("attachments/1", strings.NewReader(blob))
+	if err != nil {
+		t.Fatalf("could not write blob: %v", err)
+	}
+
+	row := map[string]string{"blob": path}
+	if err := json.NewEncoder(a.rows).Encode(row); err != nil {
+		t.Fatalf("could not write row: %v", err)
+	}
+
+	if err := a.close(); err != nil {
+		t.Fatalf("could not close archive: %v", err)
+	}
+
+	if err := a.remove(); err != nil {
+		t.Fatalf("could not remove spooled rows: %v", err)
+	}
+
+	if _, err := os.Stat(spooledPath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected spooled rows to be removed, got %v", err)
+	}
+
+	r, err := newArchiveReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("could not read archive: %v", err)
+	}
+
+	data, err := r.open(dataPath)
+	if err != nil {
+		t.Fatalf("could not open rows: %v", err)
+	}
+	defer data.Close()
+
+	var gotRow map[string]string
+	if err := json.NewDecoder(data).Decode(&gotRow); err != nil {
+		t.Fatalf("could not read row: %v", err)
+	}
+
+	if gotRow["blob"] != path {
+		t.Errorf("row blob = %q, want %q", gotRow["blob"], path)
+	}
+
+	gotBlob, err := r.readBlob(path)
+	if err != nil {
+		t.Fatalf("could not read blob: %v", err)
+	}
+
+	if string(gotBlob) != blob {
+		t.Errorf("blob has %v bytes, want %v", len(gotBlob), len(blob))
+	}
+
+	if _, err := io.Copy(io.Discard, data); err != nil {
+		t.Fatalf("could not read rows: %v", err)
+	}
+
+	if err := r.verify(); err != nil {
+		t.Errorf("could not verify archive: %v", err)
+	}
+}
@@ -0,0 +1,304 @@
+package userdata
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/pojntfx/senbara/senbara-forms/pkg/models"
+	"github.com/pojntfx/senbara/senbara-forms/pkg/persisters"
+)
+
+const (
+	EntityNameExportedJournalEntry = "journalEntry"
+	EntityNameExportedContact      = "contact"
+	EntityNameExportedDebt         = "debt"
+	EntityNameExportedActivity     = "activity"
+	EntityNameExportedContactPhoto = "contactPhoto"
+	EntityNameExportedCustomField  = "customField"
+	EntityNameExportedAttachment   = "attachment"
+	EntityNameExportedRelationship = "relationship"
+)
+
+var (
+	ErrCouldNotReadUserData  = errors.New("could not read user data")
+	ErrCouldNotWriteUserData = errors.New("could not write user data")
+	ErrUnknownEntityName     = errors.New("unknown entity name")
+	ErrMissingBlob           = errors.New("missing blob")
+)
+
+// Export writes the user data of a namespace to `w` as JSON lines, with the binary data of
+// contact photos and attachments included in the rows
+func Export(ctx context.Context, persister *persisters.Persister, key []byte, namespace string, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+
+	return export(ctx, persister, key, namespace, func(row any) error {
+		if err := encoder.Encode(row); err != nil {
+			return errors.Join(ErrCouldNotWriteUserData, err)
+		}
+
+		return nil
+	}, nil, nil)
+}
+
+// export passes the user data of a namespace to `writeRow`. If `writeBlob` is set, the binary data of contact
+// photos and attachments is streamed to it instead, and the rows reference the path it returns. If `onJournalSkipped`
+// is set, a journal that can't be decrypted with `key` is skipped instead of failing, and it is called.
+func export(
+	ctx context.Context,
+	persister *persisters.Persister,
+
+	key []byte,
+	namespace string,
+
+	writeRow func(row any) error,
+	writeBlob func(name string, data io.Reader) (string, error),
+	onJournalSkipped func() error,
+) error {
+	return persister.GetUserData(
+		ctx,
+
+		key,
+		namespace,
+
+		func(journalEntry models.ExportedJournalEntry) error {
+			journalEntry.ExportedEntityIdentifier.EntityName = EntityNameExportedJournalEntry
+
+			return writeRow(journalEntry)
+		},
+		func(contact models.ExportedContact) error {
+			contact.ExportedEntityIdentifier.EntityName = EntityNameExportedContact
+
+			return writeRow(contact)
+		},
+		func(debt models.ExportedDebt) error {
+			debt.ExportedEntityIdentifier.EntityName = EntityNameExportedDebt
+
+			return writeRow(debt)
+		},
+		func(activity models.ExportedActivity) error {
+			activity.ExportedEntityIdentifier.EntityName = EntityNameExportedActivity
+
+			return writeRow(activity)
+		},
+		func(contactPhoto models.ExportedContactPhoto) error {
+			contactPhoto.ExportedEntityIdentifier.EntityName = EntityNameExportedContactPhoto
+
+			if writeBlob != nil {
+				blob, err := writeBlob(fmt.Sprintf("contactPhotos/%v", contactPhoto.ID), bytes.NewReader(contactPhoto.Data))
+				if err != nil {
+					return errors.Join(ErrCouldNotWriteUserData, err)
+				}
+
+				contactPhoto.Blob = blob
+				contactPhoto.Data = nil
+			}
+
+			return writeRow(contactPhoto)
+		},
+		func(customField models.ExportedCustomField) error {
+			customField.ExportedEntityIdentifier.EntityName = EntityNameExportedCustomField
+
+			return writeRow(customField)
+		},
+		func(attachment models.ExportedAttachment, data io.Reader) error {
+			attachment.ExportedEntityIdentifier.EntityName = EntityNameExportedAttachment
+
+			if writeBlob != nil {
+				blob, err := writeBlob(fmt.Sprintf("attachments/%v", attachment.ID), data)
+				if err != nil {
+					return errors.Join(ErrCouldNotWriteUserData, err)
+				}
+
+				attachment.Blob = blob
+			} else {
+				// JSON lines can only include the data inline
+				var err error
+				if attachment.Data, err = io.ReadAll(data); err != nil {
+					return errors.Join(ErrCouldNotWriteUserData, err)
+				}
+			}
+
+			return writeRow(attachment)
+		},
+		func(relationship models.ExportedRelationship) error {
+			relationship.ExportedEntityIdentifier.EntityName = EntityNameExportedRelationship
+
+			return writeRow(relationship)
+		},
+		onJournalSkipped,
+	)
+}
+
+// Import creates the user data in the JSON lines read from `r` in a namespace. Either all of it is created or none.
+func Import(ctx context.Context, persister *persisters.Persister, key []byte, namespace string, r io.Reader) error {
+	return importRows(ctx, persister, key, namespace, json.NewDecoder(r), nil, nil)
+}
+
+// importRows creates the user data in the rows read from `decoder`. Rows that reference binary data
+// instead of including it are resolved with `readBlob`, and if `verify` is set, the transaction is
+// only committed if it succeeds.
+func importRows(
+	ctx context.Context,
+	persister *persisters.Persister,
+
+	key []byte,
+	namespace string,
+
+	decoder *json.Decoder,
+	readBlob func(path string) ([]byte, error),
+	verify func() error,
+) error {
+	createJournalEntry,
+		createContact,
+		createDebt,
+		createActivity,
+		createContactPhoto,
+		createCustomField,
+		createAttachment,
+		createRelationship,
+
+		commit,
+		rollback,
+
+		err := persister.CreateUserData(ctx, key, namespace)
+	if err != nil {
+		return err
+	}
+	defer rollback()
+
+	getBlob := func(path string, data []byte) ([]byte, error) {
+		if path == "" {
+			return data, nil
+		}
+
+		if readBlob == nil {
+			return nil, fmt.Errorf("%w: %v", ErrMissingBlob, path)
+		}
+
+		return readBlob(path)
+	}
+
+	for {
+		var b json.RawMessage
+		if err := decoder.Decode(&b); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return errors.Join(ErrCouldNotReadUserData, err)
+		}
+
+		var entityIdentifier models.ExportedEntityIdentifier
+		if err := json.Unmarshal(b, &entityIdentifier); err != nil {
+			return errors.Join(ErrCouldNotReadUserData, err)
+		}
+
+		switch entityIdentifier.EntityName {
+		case EntityNameExportedJournalEntry:
+			var journalEntry models.ExportedJournalEntry
+			if err := json.Unmarshal(b, &journalEntry); err != nil {
+				return errors.Join(ErrCouldNotReadUserData, err)
+			}
+
+			if err := createJournalEntry(journalEntry); err != nil {
+				return err
+			}
+
+		case EntityNameExportedContact:
+			var contact models.ExportedContact
+			if err := json.Unmarshal(b, &contact); err != nil {
+				return errors.Join(ErrCouldNotReadUserData, err)
+			}
+
+			if err := createContact(contact); err != nil {
+				return err
+			}
+
+		case EntityNameExportedDebt:
+			var debt models.ExportedDebt
+			if err := json.Unmarshal(b, &debt); err != nil {
+				return errors.Join(ErrCouldNotReadUserData, err)
+			}
+
+			if err := createDebt(debt); err != nil {
+				return err
+			}
+
+		case EntityNameExportedActivity:
+			var activity models.ExportedActivity
+			if err := json.Unmarshal(b, &activity); err != nil {
+				return errors.Join(ErrCouldNotReadUserData, err)
+			}
+
+			if err := createActivity(activity); err != nil {
+				return err
+			}
+
+		case EntityNameExportedContactPhoto:
+			var contactPhoto models.ExportedContactPhoto
+			if err := json.Unmarshal(b, &contactPhoto); err != nil {
+				return errors.Join(ErrCouldNotReadUserData, err)
+			}
+
+			if contactPhoto.Data, err = getBlob(contactPhoto.Blob, contactPhoto.Data); err != nil {
+				return err
+			}
+
+			if err := createContactPhoto(contactPhoto); err != nil {
+				return err
+			}
+
+		case EntityNameExportedCustomField:
+			var customField models.ExportedCustomField
+			if err := json.Unmarshal(b, &customField); err != nil {
+				return errors.Join(ErrCouldNotReadUserData, err)
+			}
+
+			if err := createCustomField(customField); err != nil {
+				return err
+			}
+
+		case EntityNameExportedAttachment:
+			var attachment models.ExportedAttachment
+			if err := json.Unmarshal(b, &attachment); err != nil {
+				return errors.Join(ErrCouldNotReadUserData, err)
+			}
+
+			if attachment.Data, err = getBlob(attachment.Blob, attachment.Data); err != nil {
+				return err
+			}
+
+			if err := createAttachment(attachment); err != nil {
+				return err
+			}
+
+		case EntityNameExportedRelationship:
+			var relationship models.ExportedRelationship
+			if err := json.Unmarshal(b, &relationship); err != nil {
+				return errors.Join(ErrCouldNotReadUserData, err)
+			}
+
+			if err := createRelationship(relationship); err != nil {
+				return err
+			}
+
+		default:
+			log.Println("Skipping import error:", ErrUnknownEntityName, entityIdentifier.EntityName)
+
+			continue
+		}
+	}
+
+	if verify != nil {
+		if err := verify(); err != nil {
+			return err
+		}
+	}
+
+	return commit()
+}
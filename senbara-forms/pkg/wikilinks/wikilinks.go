@@ -0,0 +1,108 @@
+package wikilinks
+
+import (
+	"bytes"
+	"net/url"
+	"strconv"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+const (
+	// parserPriority makes wiki links take precedence over the built-in link parser, which also triggers on `[`
+	parserPriority = 199
+
+	journalEntryPrefix = "journal:"
+)
+
+var (
+	// Extension resolves `[[Contact Name]]` and `[[journal:ID]]` to links; both support an optional label with `[[Target|Label]]`
+	Extension goldmark.Extender = &extender{}
+
+	contactNamesKey = parser.NewContextKey()
+)
+
+type extender struct{}
+
+func (e *extender) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithInlineParsers(util.Prioritized(&wikiLinkParser{}, parserPriority)))
+}
+
+type wikiLinkParser struct{}
+
+func (p *wikiLinkParser) Trigger() []byte {
+	return []byte{'['}
+}
+
+func (p *wikiLinkParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, segment := block.PeekLine()
+	if !bytes.HasPrefix(line, []byte("[[")) {
+		return nil
+	}
+
+	end := bytes.Index(line[2:], []byte("]]"))
+	if end < 0 {
+		return nil
+	}
+
+	content := line[2 : 2+end]
+	if bytes.ContainsAny(content, "[]\n") {
+		return nil
+	}
+
+	target, rawLabel, hasLabel := bytes.Cut(content, []byte("|"))
+	target = bytes.TrimSpace(target)
+	if len(target) == 0 {
+		return nil
+	}
+
+	label := text.NewSegment(segment.Start+2, segment.Start+2+end)
+	if hasLabel {
+		label = text.NewSegment(label.Stop-len(rawLabel), label.Stop)
+	}
+	label = label.TrimLeftSpace(block.Source())
+	label = label.TrimRightSpace(block.Source())
+	if label.IsEmpty() {
+		return nil
+	}
+
+	link := ast.NewLink()
+	if rid, ok := bytes.CutPrefix(target, []byte(journalEntryPrefix)); ok {
+		id, err := strconv.Atoi(string(rid))
+		if err != nil {
+			return nil
+		}
+
+		link.Destination = []byte("/journal/view?id=" + strconv.Itoa(id))
+	} else {
+		name := string(target)
+
+		link.Destination = []byte("/contacts/resolve?name=" + url.QueryEscape(name))
+
+		names, _ := pc.Get(contactNamesKey).([]string)
+		pc.Set(contactNamesKey, append(names, name))
+	}
+
+	link.AppendChild(link, ast.NewTextSegment(label))
+
+	block.Advance(2 + end + 2)
+
+	return link
+}
+
+// ExtractContactNames returns the names of all contacts that are linked to in a Markdown document, in order of appearance
+func ExtractContactNames(source []byte) []string {
+	p := goldmark.DefaultParser()
+	p.AddOptions(parser.WithInlineParsers(util.Prioritized(&wikiLinkParser{}, parserPriority)))
+
+	pc := parser.NewContext()
+	p.Parse(text.NewReader(source), parser.WithContext(pc))
+
+	names, _ := pc.Get(contactNamesKey).([]string)
+
+	return names
+}